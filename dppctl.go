@@ -4,31 +4,140 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"os"
-    "gopkg.in/yaml.v3"
+	"os/signal"
+	"strings"
+	"syscall"
 	
+	"github.com/data-engineering-helpers/dppctl/report"
 	"github.com/data-engineering-helpers/dppctl/utilities"
 	"github.com/data-engineering-helpers/dppctl/workflow"
 )
 
 const AppVersion = "0.0.2-alpha.1"
 
+// Default deployment specification file, when no `-f` option is given
+const defaultSpecFilepath = "depl/aws-dev-sample.yaml"
+
+// The `-f` option may be repeated, for the specification files
+// to be layered (each file being overlaid onto the previous ones)
+type specFileList []string
+
+func (l *specFileList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *specFileList) Set(specFilepath string) error {
+	*l = append(*l, specFilepath)
+	return nil
+}
+
 var (
 	versionFlag bool
-	specFilepath string
+	specFilepaths specFileList
 	command string
+	outputFormat string
+	parallelism int
+	lockedFlag bool
+	updateLockFlag bool
+	lockFilepath string
+	distDir string
+	clusterFlag bool
+	promoteOptions workflow.PromoteOptions
 )
 
 func init() {
 	flag.BoolVar(&versionFlag, "v", false, "Shows the current version")
 
-	flag.StringVar(&specFilepath, "f",  "depl/aws-dev-sample.yaml",
-		"The `name` of the deployment YAML specification file.")
+	flag.Var(&specFilepaths, "f",
+		"The `name` of the deployment YAML specification file (default \"" +
+		defaultSpecFilepath + "\"). It may be repeated, for overlays.")
 
 	flag.StringVar(&command, "c",  "check",
-		"The command to perform.")
+		"The command to perform (schema, validate, render, check, plan, deploy, publish or promote).")
+
+	flag.StringVar(&outputFormat, "o",  report.FormatTable,
+		"The `format` of the report (table, json or yaml).")
+
+	flag.IntVar(&parallelism, "p",  workflow.DefaultParallelism,
+		"The maximum `number` of checks run at the same time.")
+
+	flag.BoolVar(&lockedFlag, "locked", false,
+		"Refuse to deploy when the live state differs from the lock file, which the check then requires instead of writing it when missing.")
+
+	flag.BoolVar(&updateLockFlag, "update-lock", false,
+		"Write the lock file with the live state observed by the check, even when it differs from the existing one.")
+
+	flag.StringVar(&lockFilepath, "lock", "",
+		"The `name` of the lock file (default \"" + workflow.LockFilename +
+		"\", next to the last specification file).")
+
+	flag.StringVar(&distDir, "dist", "",
+		"The `directory` of the build artifacts: the Python wheels and sdists to be published (default \"" +
+		workflow.DefaultDistDir + "\"), or the files the published assets are verified against by the check.")
+
+	flag.BoolVar(&clusterFlag, "cluster", false,
+		"Also look up the cluster of the compute engine during the check (a cluster which is not running, e.g., a transient one, is only a warning).")
+
+	flag.StringVar(&promoteOptions.TargetRepo, "target-repo", "",
+		"The `name` of the repository the modules are promoted to, within the domain of the artifact repository.")
+
+	flag.BoolVar(&promoteOptions.DryRun, "dry-run", false,
+		"Report what would be promoted, without copying anything.")
+
+	flag.BoolVar(&promoteOptions.Force, "force", false,
+		"Overwrite the versions already in the target repository in another revision.")
+}
+
+// The lock file, given with the -lock option or next to the specification
+func lockFile() string {
+	if lockFilepath != "" {
+		return lockFilepath
+	}
+	return workflow.LockFilepath(specFilepaths)
+}
+
+// The report goes onto the standard output, while the logs go onto
+// the standard error, so that the report may be piped to other tools
+func renderReport(commandReport interface{}) {
+	err := report.Render(os.Stdout, outputFormat, commandReport)
+	if err != nil {
+		log.Fatalf("The report cannot be rendered: %v", err)
+	}
+}
+
+// Output format of a command rendering a document rather than a report
+// (e.g., schema, render): its own default format, unless the -o option
+// gives one of the formats it supports
+func commandFormat(defaultFormat string, supportedFormats ...string) string {
+	formatSet := false
+	flag.Visit(func(f *flag.Flag) {
+		formatSet = formatSet || f.Name == "o"
+	})
+	if !formatSet {
+		return defaultFormat
+	}
+	for _, supportedFormat := range supportedFormats {
+		if outputFormat == supportedFormat {
+			return outputFormat
+		}
+	}
+	log.Fatalf("The %s command does not support the %q output format (supported formats: %v)",
+		command, outputFormat, supportedFormats)
+	return ""
+}
+
+// Specification of the deployment
+func readSpecFile() utilities.SpecFile {
+	deplSpec, err := utilities.ReadSpecFile(specFilepaths...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println("Parsed spec file(s):", specFilepaths.String())
+	return deplSpec
 }
 
 func main() {
@@ -37,31 +146,135 @@ func main() {
 	// the time, source file, and line number.
 	log.SetPrefix("[dppctl] ")
 	log.SetFlags(0)
+	log.SetOutput(os.Stderr)
 
 	//
 	flag.Parse()
+	if len(specFilepaths) == 0 {
+		specFilepaths = specFileList{defaultSpecFilepath}
+	}
 	if versionFlag {
       log.Println(AppVersion)
       os.Exit(0)
     }
 
-	// Specification of the deployment
-	deplSpec, err := utilities.ReadSpecFile(specFilepath)
-	if err != nil {
-		log.Print(err)
+	// The output format is checked before any (potentially long) command
+	// is performed
+	if !report.IsKnownFormat(outputFormat) {
+		log.Fatalf("The %q output format is not known (known formats: %v)",
+			outputFormat, report.Formats)
 	}
-	log.Println("Parsed spec file: ", deplSpec)
 
-	deplSpecStruct, err := yaml.Marshal(&deplSpec)
-	if err != nil {
-		log.Fatalf("error: %v", err)
-	}
-	log.Println("Spec file dump:\n", string(deplSpecStruct))
+	// The calls to the cloud services are cancelled on Ctrl-C (SIGINT)
+	// or when the process is asked to terminate (SIGTERM)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt,
+		syscall.SIGTERM)
+	defer stop()
 
 	//
 	switch command {
+	case "validate":
+		validationReport, err := workflow.Validate(specFilepaths...)
+		renderReport(validationReport)
+		if err != nil {
+			log.Fatalf("The validation failed: %v", err)
+		}
+	case "schema":
+		// A JSON Schema is rendered as JSON, unless YAML is asked for
+		schemaFormat := commandFormat(report.FormatJSON, report.FormatJSON,
+			report.FormatYAML)
+		err := report.Render(os.Stdout, schemaFormat,
+			utilities.GenerateSpecSchema())
+		if err != nil {
+			log.Fatalf("The JSON Schema cannot be rendered: %v", err)
+		}
+	case "render":
+		// Effective specification, once the layered files are merged,
+		// as commented YAML only
+		commandFormat(report.FormatYAML, report.FormatYAML)
+		layers, err := utilities.LoadSpecLayers(specFilepaths...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		rendered, err := layers.Render()
+		if err != nil {
+			log.Fatalf("The specification cannot be rendered: %v", err)
+		}
+		os.Stdout.Write(rendered)
 	case "check":
-		workflow.Check(deplSpec)
+		if lockedFlag && updateLockFlag {
+			log.Fatal("The -locked and -update-lock options cannot be combined")
+		}
+		deplSpec := readSpecFile()
+		checkReport, err := workflow.Check(ctx, deplSpec, parallelism, distDir,
+			clusterFlag)
+		renderReport(checkReport)
+		if err != nil {
+			log.Fatal("The check failed")
+		}
+
+		// The observed state is compared to the lock, which is only
+		// written when missing, or when asked for
+		written, diffs, err := workflow.ApplyLock(lockFile(),
+			workflow.NewLock(deplSpec, checkReport), updateLockFlag, lockedFlag)
+		if err != nil {
+			log.Fatalf("The lock file cannot be applied: %v", err)
+		}
+		if written {
+			log.Println("Lock file written:", lockFile())
+			break
+		}
+		if len(diffs) > 0 {
+			log.Fatalf("The check failed: the live state differs from the %s lock file (once reviewed, the changes are locked with -update-lock):\n  - %s",
+				lockFile(), strings.Join(diffs, "\n  - "))
+		}
+		log.Println("The live state matches the lock file:", lockFile())
+	case "deploy":
+		var lock *workflow.Lock
+		if lockedFlag {
+			locked, err := workflow.ReadLock(lockFile())
+			if err != nil {
+				log.Fatal(err)
+			}
+			lock = &locked
+		}
+		deployReport, err := workflow.Deploy(ctx, readSpecFile(), lock)
+		renderReport(deployReport)
+		if err != nil {
+			log.Fatalf("The deployment failed: %v", err)
+		}
+	case "publish":
+		if distDir == "" {
+			distDir = workflow.DefaultDistDir
+		}
+		publishReport, err := workflow.Publish(ctx, readSpecFile(), distDir)
+		renderReport(publishReport)
+		if err != nil {
+			log.Fatalf("The publication failed: %v", err)
+		}
+	case "promote":
+		promoteReport, err := workflow.Promote(ctx, readSpecFile(),
+			promoteOptions)
+		renderReport(promoteReport)
+		if err != nil {
+			log.Fatalf("The promotion failed: %v", err)
+		}
+	case "plan":
+		planItems, err := workflow.Plan(ctx, readSpecFile())
+		if err != nil {
+			log.Fatalf("The plan cannot be computed: %v", err)
+		}
+		renderReport(workflow.NewPlanReport(planItems))
+
+		// As with `terraform plan -detailed-exitcode`, a drift between
+		// the specification and the observed state is reported with
+		// the 2 exit code. The unmanaged resources, which the deployment
+		// leaves as is, are not a drift
+		if workflow.HasChanges(planItems) {
+			os.Exit(2)
+		}
+	default:
+		log.Fatalf("The %s command is not known", command)
 	}
 }
 
@@ -4,8 +4,8 @@
 package main
 
 import (
-  "testing"
-	
+	"testing"
+
 	"github.com/data-engineering-helpers/dppctl/utilities"
 )
 
@@ -23,4 +23,3 @@ func TestReadSpecFile(t *testing.T) {
 	}
 	
 }
-
@@ -0,0 +1,78 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/report/format.go
+//
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+)
+
+// Presentation of the structures returned by the service layer, as
+// human-readable (log) lines
+
+// Time in the RFC 3339 format, or a dash when the time is not known
+func FormatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatOrigin(origin service.PackageOrigin) string {
+	return fmt.Sprintf("(domain-entry-point=%s, repository-name=%s, origin-type=%s)",
+		origin.ExternalConnectionName, origin.RepositoryName,
+		origin.OriginType)
+}
+
+func FormatS3Object(object service.S3Object) string {
+	return fmt.Sprintf("Key=%s size=%d", object.Key, object.Size)
+}
+
+func FormatPackageVersion(pkgVersion service.PackageVersion) string {
+	return fmt.Sprintf("Pkg-name=%s Version=%s Status=%s Revision=%s Origin=%s",
+		pkgVersion.Package, pkgVersion.Version, pkgVersion.Status,
+		pkgVersion.Revision, formatOrigin(pkgVersion.Origin))
+}
+
+func FormatPackageVersionDetail(pkgDetails service.PackageVersionDetail) string {
+	return fmt.Sprintf("Pkg-name=%s Display-name=%s Version=%s Status=%s Revision=%s Homepage=%s Namespace=%s Source-code-repo=%s Published-time=%s Licenses=%s Origin=%s",
+		pkgDetails.Package, pkgDetails.DisplayName, pkgDetails.Version,
+		pkgDetails.Status, pkgDetails.Revision, pkgDetails.HomePage,
+		pkgDetails.Namespace, pkgDetails.SourceCodeRepository,
+		FormatTime(pkgDetails.PublishedTime),
+		strings.Join(pkgDetails.Licenses, ","),
+		formatOrigin(pkgDetails.Origin))
+}
+
+func FormatImageDetail(imageDetail service.ImageDetail) string {
+	// The severities are sorted, for the output to be stable
+	severities := []string{}
+	for severity := range imageDetail.ScanFindings {
+		severities = append(severities, severity)
+	}
+	sort.Strings(severities)
+	findings := []string{}
+	for _, severity := range severities {
+		findings = append(findings, fmt.Sprintf("%s:%d", severity,
+			imageDetail.ScanFindings[severity]))
+	}
+
+	return fmt.Sprintf("Image-tags=%s Image-digest=%s Image-pushed-at=%s Image-size-in-bytes=%d Artifact-media-type=%s Last-recorded-pull-time=%s Image-manifest-media-type=%s Image-scan-status=%s Image-scan-findings-summary=%s",
+		strings.Join(imageDetail.Tags, ","), imageDetail.Digest,
+		FormatTime(imageDetail.PushedAt), imageDetail.SizeInBytes,
+		imageDetail.ArtifactMediaType,
+		FormatTime(imageDetail.LastRecordedPullTime),
+		imageDetail.ManifestMediaType, imageDetail.ScanStatus,
+		strings.Join(findings, ","))
+}
+
+func FormatClusterDetail(clusterDetail service.ClusterDetail) string {
+	return fmt.Sprintf("Id=%s Name=%s Release-label=%s State=%s",
+		clusterDetail.Id, clusterDetail.Name, clusterDetail.ReleaseLabel,
+		clusterDetail.State)
+}
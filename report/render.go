@@ -0,0 +1,77 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/report/render.go
+//
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats (see the `-o` command-line option)
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+)
+
+var Formats = []string{FormatTable, FormatJSON, FormatYAML}
+
+func IsKnownFormat(format string) bool {
+	for _, knownFormat := range Formats {
+		if format == knownFormat {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports, which know how to render themselves as aligned tables. Any
+// report may be rendered as JSON or YAML, from its json/yaml struct tags
+type TableRenderer interface {
+	RenderTable(w io.Writer) error
+}
+
+/**
+ * Render a report onto the given writer (usually the standard output,
+ * the logs going onto the standard error), in the given format
+ */
+func Render(w io.Writer, format string, report interface{}) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+		return encoder.Close()
+
+	case FormatTable:
+		tableRenderer, ok := report.(TableRenderer)
+		if !ok {
+			return fmt.Errorf("the %T report cannot be rendered as a table",
+				report)
+		}
+		return tableRenderer.RenderTable(w)
+	}
+
+	return fmt.Errorf("the %q output format is not known (known formats: %v)",
+		format, Formats)
+}
+
+/**
+ * Writer aligning the tab-separated columns of the rows written onto it.
+ * It has to be flushed once all the rows have been written
+ */
+func NewTableWriter(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+}
@@ -0,0 +1,84 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/report/render_test.go
+//
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Report rendered as a table, in the same way as the reports of workflow
+type exampleReport struct {
+	Name    string   `json:"name" yaml:"name"`
+	Modules []string `json:"modules" yaml:"modules"`
+}
+
+func (r exampleReport) RenderTable(w io.Writer) error {
+	tw := NewTableWriter(w)
+	fmt.Fprintln(tw, "NAME\tMODULE")
+	for _, module := range r.Modules {
+		fmt.Fprintf(tw, "%s\t%s\n", r.Name, module)
+	}
+	return tw.Flush()
+}
+
+/**
+ * Check that the reports are rendered in every output format, and that
+ * a report without any table, or an unknown format, is an error
+ */
+func TestRender(t *testing.T) {
+	exampleRep := exampleReport{Name: "example",
+		Modules: []string{"example-pkg", "other-example-pkg"}}
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{FormatJSON, `{
+  "name": "example",
+  "modules": [
+    "example-pkg",
+    "other-example-pkg"
+  ]
+}
+`},
+		{FormatYAML, `name: example
+modules:
+  - example-pkg
+  - other-example-pkg
+`},
+		{FormatTable, `NAME     MODULE
+example  example-pkg
+example  other-example-pkg
+`},
+	}
+	for _, test := range tests {
+		var rendered bytes.Buffer
+		err := Render(&rendered, test.format, exampleRep)
+		if err != nil || rendered.String() != test.expected {
+			t.Errorf(`Render(%s) = %q, %v, expected %q`, test.format,
+				rendered.String(), err, test.expected)
+		}
+	}
+
+	// Without any RenderTable method, only JSON and YAML are possible
+	noTable := struct {
+		Name string `json:"name" yaml:"name"`
+	}{"example"}
+	err := Render(io.Discard, FormatTable, noTable)
+	if err == nil || !strings.Contains(err.Error(), "cannot be rendered as a table") {
+		t.Errorf(`Render(table) = %v, expected an error`, err)
+	}
+	err = Render(io.Discard, FormatJSON, noTable)
+	if err != nil {
+		t.Errorf(`Render(json) = %v`, err)
+	}
+
+	err = Render(io.Discard, "xml", exampleRep)
+	if err == nil || !strings.Contains(err.Error(), `the "xml" output format is not known`) {
+		t.Errorf(`Render(xml) = %v, expected an error`, err)
+	}
+}
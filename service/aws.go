@@ -8,43 +8,80 @@ import (
 	"fmt"
 	"io/ioutil"
 	"time"
-	"log"
-	"flag"
 	"errors"
 	"net/http"
 	"encoding/json"
 	"encoding/base64"
 	"bytes"
+	"os"
+	"strings"
+	"sync"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/codeartifact"
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/aws/aws-sdk-go-v2/service/emr"
+	emrtypes "github.com/aws/aws-sdk-go-v2/service/emr/types"
 	awscatypes "github.com/aws/aws-sdk-go-v2/service/codeartifact/types"	
 	"github.com/aws/aws-sdk-go-v2/service/mwaa"
 	"github.com/aws/smithy-go/middleware"
 )
 
+// AWS configurations, indexed by region and IAM role, so that the credentials
+// are loaded (and the roles assumed) only once per run
 var (
-	awsConfig aws.Config
-	region    string
+	awsConfigs      = map[string]aws.Config{}
+	awsConfigsMutex sync.Mutex
 )
 
-func init() {
-	flag.StringVar(
-		&region, "region", "eu-west-1",	"The `region` of the AWS project.")
+/**
+ * AWS configuration for a given region and, optionally, a given IAM role
+ * to be assumed (e.g., for a cross-account access)
+ *
+ * The SDK's default configuration is used first, loading the config
+ * and credentials values from the environment variables, shared
+ * credentials, and shared configuration files. When the region is empty,
+ * the default region of that configuration is used. When an IAM role
+ * is given, it is assumed through STS with those default credentials
+ * References:
+ *   + https://aws.github.io/aws-sdk-go-v2/docs/configuring-sdk/
+ *   + https://github.com/aws/aws-sdk-go-v2/blob/main/credentials/stscreds/assume_role_provider.go
+*/
+func AWSConfig(ctx context.Context, region string,
+	roleArn string) (aws.Config, error) {
+	awsConfigsMutex.Lock()
+	defer awsConfigsMutex.Unlock()
+
+	cacheKey := region + "|" + roleArn
+	if cfg, found := awsConfigs[cacheKey]; found {
+		return cfg, nil
+	}
 
-    // Using the SDK's default configuration, loading additional config
-    // and credentials values from the environment variables, shared
-    // credentials, and shared configuration files
-    cfg, err := config.LoadDefaultConfig(
-		context.TODO(),
-		config.WithRegion(region))
-    if err != nil {
-        log.Fatalf("unable to load SDK config, %v", err)
-    }
-	awsConfig = cfg
+	optFns := []func(*config.LoadOptions) error{}
+	if region != "" {
+		optFns = append(optFns, config.WithRegion(region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return cfg, &Error{Service: "config", Operation: "LoadDefaultConfig",
+			Err: err}
+	}
+
+	if roleArn != "" {
+		stsSvc := sts.NewFromConfig(cfg)
+		roleProvider := stscreds.NewAssumeRoleProvider(stsSvc, roleArn,
+			func(options *stscreds.AssumeRoleOptions) {
+				options.RoleSessionName = "dppctl"
+			})
+		cfg.Credentials = aws.NewCredentialsCache(roleProvider)
+	}
+
+	awsConfigs[cacheKey] = cfg
+	return cfg, nil
 }
 
 // A Response struct to map the MWAA CLI API response
@@ -56,8 +93,11 @@ type MWAAResponse struct {
 /**
  * AWS STS - Get caller identity
  */
-func AWSGetCallerIdentity() (string, error) {
-	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+func AWSGetCallerIdentity(ctx context.Context,
+	awsConfig aws.Config) (CallerIdentity, error) {
+	var sts_identity CallerIdentity
+
+	ctx, cancelFn := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelFn()
 
     // Create an Amazon STS service client
@@ -67,13 +107,14 @@ func AWSGetCallerIdentity() (string, error) {
 	params := &sts.GetCallerIdentityInput{}
 	output, err := svc.GetCallerIdentity(ctx, params)
 	if err != nil {
-		log.Fatalf("expect no error, got %v", err)
+		return sts_identity, awsError("sts", "GetCallerIdentity", err)
 	}
 
-	sts_identity := fmt.Sprintf("UserId=%s Account=%s Arn=%s",
-		aws.ToString(output.UserId),
-		aws.ToString(output.Account),
-		aws.ToString(output.Arn))
+	sts_identity = CallerIdentity{
+		UserId: aws.ToString(output.UserId),
+		Account: aws.ToString(output.Account),
+		Arn: aws.ToString(output.Arn),
+	}
 
     //
     return sts_identity, nil
@@ -81,36 +122,91 @@ func AWSGetCallerIdentity() (string, error) {
 
 /**
  * AWS S3 - List of objects within a specific folder (prefix)
- */
-func AWSS3List(bucketName string, prefix string) ([]string, error) {
-    messages := []string {}
-
-    //
-    if bucketName == "" {
-        return messages, errors.New("empty bucket name")
-    }
+ *
+ * ListObjectsV2 returns at most 1,000 keys per call: the pages are followed
+ * with the paginator of the SDK, and the objects are handed over
+ * to the callback as soon as their page has arrived
+ * References:
+ *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/s3/api_op_ListObjectsV2.go
+*/
+func AWSS3List(ctx context.Context, awsConfig aws.Config, bucketName string,
+	prefix string, opts ListOptions, fn func(S3Object) error) error {
+	//
+	if bucketName == "" {
+		return invalidInputError("s3", "ListObjectsV2", "empty bucket name")
+	}
 
-    // Create an Amazon S3 service client
-    svc := s3.NewFromConfig(awsConfig)
+	// Create an Amazon S3 service client
+	svc := s3.NewFromConfig(awsConfig)
 
-    // Get the first page of results for ListObjectsV2 for a bucket
+	// Build the request with its input parameters
 	params := &s3.ListObjectsV2Input{
 		Bucket: aws.String(bucketName),
 		Prefix: aws.String(prefix),
 	}
-    output, err := svc.ListObjectsV2(context.TODO(), params)
-    if err != nil {
-		log.Fatal(err)
-    }
+	if opts.PageSize > 0 {
+		params.MaxKeys = aws.Int32(opts.PageSize)
+	}
 
-    for _, object := range output.Contents {
-		message := fmt.Sprintf("Key=%s size=%d",
-			aws.ToString(object.Key), object.Size)
-		messages = append(messages, message)
-    }
+	//
+	paginator := s3.NewListObjectsV2Paginator(svc, params)
+	return listPages(ctx, opts, paginator.HasMorePages,
+		func(ctx context.Context) ([]S3Object, error) {
+			output, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, awsError("s3", "ListObjectsV2", err)
+			}
+			objects := []S3Object{}
+			for _, object := range output.Contents {
+				objects = append(objects, S3Object{
+					Key: aws.ToString(object.Key),
+					Size: aws.ToInt64(object.Size),
+					ETag: aws.ToString(object.ETag),
+					LastModified: aws.ToTime(object.LastModified),
+					StorageClass: string(object.StorageClass),
+				})
+			}
+			return objects, nil
+		}, fn)
+}
 
-    //
-    return messages, nil
+/**
+ * AWS S3 - Upload a local file onto a specific object key
+ * References:
+ *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/s3/api_op_PutObject.go
+*/
+func AWSS3Upload(ctx context.Context, awsConfig aws.Config,
+	bucketName string, key string, filepath string) (string, error) {
+	etag := ""
+
+	//
+	if bucketName == "" {
+		return etag, invalidInputError("s3", "PutObject", "empty bucket name")
+	}
+
+	file, err := os.Open(filepath)
+	if err != nil {
+		return etag, err
+	}
+	defer file.Close()
+
+	// Create an Amazon S3 service client
+	svc := s3.NewFromConfig(awsConfig)
+
+	// Upload the content of the file
+	params := &s3.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key: aws.String(key),
+		Body: file,
+	}
+	output, err := svc.PutObject(ctx, params)
+	if err != nil {
+		return etag, awsError("s3", "PutObject", err)
+	}
+	etag = aws.ToString(output.ETag)
+
+	//
+	return etag, nil
 }
 
 /**
@@ -120,28 +216,31 @@ func AWSS3List(bucketName string, prefix string) ([]string, error) {
  *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/codeartifact/api_op_ListDomains.go
  *   + https://docs.aws.amazon.com/codeartifact/latest/APIReference/API_DomainSummary.html
 */
-func AWSCodeArtifactListDomains() ([]string, error) {
-    messages := []string {}
-
-    // Using the Config value, create the CodeArtifact client
-    svc := codeartifact.NewFromConfig(awsConfig)
-
-    // Build the request with its input parameters
-	params := &codeartifact.ListDomainsInput{}
-    resp, err := svc.ListDomains(context.TODO(), params)
-    if err != nil {
-        log.Fatalf("failed to list domains, %v", err)
-    }
+func AWSCodeArtifactListDomains(ctx context.Context, awsConfig aws.Config,
+	opts ListOptions, fn func(string) error) error {
+	// Using the Config value, create the CodeArtifact client
+	svc := codeartifact.NewFromConfig(awsConfig)
+
+	// Build the request with its input parameters
+	params := &codeartifact.ListDomainsInput{
+		MaxResults: opts.pageSize(),
+	}
 
 	//
-    for _, domain := range resp.Domains {
-		message := fmt.Sprintf("Name=%s Status=%s",
-			aws.ToString(domain.Name), domain.Status)
-		messages = append(messages, message)
-    }
-
-    //
-    return messages, nil
+	paginator := codeartifact.NewListDomainsPaginator(svc, params)
+	return listPages(ctx, opts, paginator.HasMorePages,
+		func(ctx context.Context) ([]string, error) {
+			resp, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, awsError("codeartifact", "ListDomains", err)
+			}
+			messages := []string{}
+			for _, domain := range resp.Domains {
+				messages = append(messages, fmt.Sprintf("Name=%s Status=%s",
+					aws.ToString(domain.Name), domain.Status))
+			}
+			return messages, nil
+		}, fn)
 }
 
 /**
@@ -154,66 +253,88 @@ func AWSCodeArtifactListDomains() ([]string, error) {
 */
 func AWSCodeArtifactFormatFromString(format string) (awscatypes.PackageFormat,
 	error) {
-	switch format {
-	case "pypi":
-		return awscatypes.PackageFormatPypi, nil
-	case "maven":
-		return awscatypes.PackageFormatMaven, nil
+	// Every format known to the SDK (e.g., npm, pypi, maven, nuget,
+	// generic, ruby, swift, cargo)
+	knownFormats := []string{}
+	for _, knownFormat := range awscatypes.PackageFormat("").Values() {
+		if string(knownFormat) == format {
+			return knownFormat, nil
+		}
+		knownFormats = append(knownFormats, string(knownFormat))
 	}
 
-	errMsg := fmt.Sprintf("The %s CodeArtifact repository format is not known")
-	return awscatypes.PackageFormatGeneric, errors.New(errMsg)
+	errMsg := fmt.Sprintf("The %s CodeArtifact repository format is not known (known formats: %s)",
+		format, strings.Join(knownFormats, ", "))
+	return awscatypes.PackageFormatGeneric,
+		invalidInputError("codeartifact", "PackageFormat", errMsg)
+}
+
+/**
+ * AWS CodeArtifact (CA) - Namespace of a package, if any: the groupId
+ * of the Maven artifacts, the scope of the npm packages (without
+ * the leading @), the namespace of the generic and Swift packages
+ * Reference: https://docs.aws.amazon.com/codeartifact/latest/ug/codeartifact-concepts.html#welcome-concepts-package-namespace
+ */
+func awsPackageNamespace(repoFormat awscatypes.PackageFormat,
+	namespace string) *string {
+	if repoFormat == awscatypes.PackageFormatNpm {
+		namespace = strings.TrimPrefix(namespace, "@")
+	}
+	if namespace == "" {
+		return nil
+	}
+	return aws.String(namespace)
 }
 
 /**
- * AWS CodeArticat (CA) - List of versions for a given package
+ * AWS CodeArticat (CA) - List of versions for a given package, optionally
+ * only those having a given status (e.g., Published)
  * References:   
  *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/codeartifact/types/types.go
  *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/codeartifact/api_op_ListPackageVersions.go
  *   + https://docs.aws.amazon.com/codeartifact/latest/APIReference/API_PackageVersionSummary.html
  *
 */
-func AWSCodeArtifactListPackageVersions(domainName string,
-	domainOwner string, repoName string, repoFormat awscatypes.PackageFormat,
-	packageName string) ([]string, error) {
-    pkgVersions := []string {}
-
-    // Using the Config value, create the CodeArtifact client
-    svc := codeartifact.NewFromConfig(awsConfig)
-
-    // Build the request with its input parameters
+func AWSCodeArtifactListPackageVersions(ctx context.Context,
+	awsConfig aws.Config,
+	domainName string, domainOwner string, repoName string,
+	repoFormat awscatypes.PackageFormat, namespace string, packageName string,
+	opts ListOptions, fn func(PackageVersion) error) error {
+	// Using the Config value, create the CodeArtifact client
+	svc := codeartifact.NewFromConfig(awsConfig)
+
+	// Build the request with its input parameters
 	params := &codeartifact.ListPackageVersionsInput{
 		Domain: aws.String(domainName),
 		DomainOwner: aws.String(domainOwner),
 		Format: repoFormat,
 		Repository: aws.String(repoName),
+		Namespace: awsPackageNamespace(repoFormat, namespace),
 		Package: aws.String(packageName),
+		Status: awscatypes.PackageVersionStatus(opts.Status),
+		MaxResults: opts.pageSize(),
 	}
-    resp, err := svc.ListPackageVersions(context.TODO(), params)
-    if err != nil {
-        log.Fatalf("failed to list the versions of the package, %v", err)
-    }
 
 	//
-    for _, versionStruct := range resp.Versions {
-		origin := versionStruct.Origin
-		domainEntryPoint := origin.DomainEntryPoint
-		externalConnectionName := aws.ToString(domainEntryPoint.ExternalConnectionName)
-		repositoryName := aws.ToString(domainEntryPoint.RepositoryName)
-		originType := origin.OriginType
-		version := aws.ToString(versionStruct.Version)
-		revision := aws.ToString(versionStruct.Revision)
-		status := versionStruct.Status
-
-		message := fmt.Sprintf("Pkg-name=%s Version=%s Status=%s Revision=%s Origin=(domain-entry-point=%s, repository-name=%s, origin-type=%s)",
-			packageName, version, status, revision,
-			externalConnectionName, repositoryName, originType)
-		//log.Println("Package details:", message)
-		pkgVersions = append(pkgVersions, message)
-    }
-
-    //
-    return pkgVersions, nil
+	paginator := codeartifact.NewListPackageVersionsPaginator(svc, params)
+	return listPages(ctx, opts, paginator.HasMorePages,
+		func(ctx context.Context) ([]PackageVersion, error) {
+			resp, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, awsError("codeartifact", "ListPackageVersions", err)
+			}
+			pkgVersions := []PackageVersion{}
+			for _, versionStruct := range resp.Versions {
+				pkgVersions = append(pkgVersions, PackageVersion{
+					Package: packageName,
+					Version: aws.ToString(versionStruct.Version),
+					Revision: aws.ToString(versionStruct.Revision),
+					Status: string(versionStruct.Status),
+					Origin: awsPackageOrigin(versionStruct.Origin),
+				})
+			}
+			return pkgVersions, nil
+		}, fn)
 }
 
 /**
@@ -224,10 +345,12 @@ func AWSCodeArtifactListPackageVersions(domainName string,
  *   + https://docs.aws.amazon.com/codeartifact/latest/APIReference/API_PackageVersionDescription.html
  *
 */
-func AWSCodeArtifactDescribePackageVersion(domainName string,
-	domainOwner string, repoName string, repoFormat awscatypes.PackageFormat,
-	packageName string, packageVersion string) (string, error) {
-    var pkgDetailsStr string
+func AWSCodeArtifactDescribePackageVersion(ctx context.Context,
+	awsConfig aws.Config,
+	domainName string, domainOwner string, repoName string,
+	repoFormat awscatypes.PackageFormat, namespace string, packageName string,
+	packageVersion string) (PackageVersionDetail, error) {
+    var pkgDetails PackageVersionDetail
 
     // Using the Config value, create the CodeArtifact client
     svc := codeartifact.NewFromConfig(awsConfig)
@@ -238,40 +361,319 @@ func AWSCodeArtifactDescribePackageVersion(domainName string,
 		DomainOwner: aws.String(domainOwner),
 		Format: repoFormat,
 		Repository: aws.String(repoName),
+		Namespace: awsPackageNamespace(repoFormat, namespace),
 		Package: aws.String(packageName),
 		PackageVersion: aws.String(packageVersion),
 	}
-    resp, err := svc.DescribePackageVersion(context.TODO(), params)
+    resp, err := svc.DescribePackageVersion(ctx, params)
     if err != nil {
-		errMsg := fmt.Sprintf("Failed to describe package for the specific versioned package, %v",
-			err)
-		log.Println(errMsg)
-        return pkgDetailsStr, err
+        return pkgDetails, awsError("codeartifact",
+			"DescribePackageVersion", err)
     }
 
 	//
 	packageVersionDesc := resp.PackageVersion
-	displayName := aws.ToString(packageVersionDesc.DisplayName)
-	homePage := aws.ToString(packageVersionDesc.HomePage)
-	licenses := packageVersionDesc.Licenses
-	namespace := aws.ToString(packageVersionDesc.Namespace)
-	publishedTime := packageVersionDesc.PublishedTime
-	revision := aws.ToString(packageVersionDesc.Revision)
-	status := packageVersionDesc.Status
-	sourceCodeRepository := aws.ToString(packageVersionDesc.SourceCodeRepository)
-	origin := packageVersionDesc.Origin
-	domainEntryPoint := origin.DomainEntryPoint
-	externalConnectionName := aws.ToString(domainEntryPoint.ExternalConnectionName)
-	repositoryName := aws.ToString(domainEntryPoint.RepositoryName)
-	originType := origin.OriginType
-
-	pkgDetailsStr = fmt.Sprintf("Pkg-name=%s Display-name=%s Version=%s Status=%s Revision=%s Homepage=%s Namespace=%s Source-code-repo=%s Published-time=%s Licenses=%s Origin=(domain-entry-point=%s, repository-name=%s, origin-type=%s)",
-		packageName, displayName, packageVersion, status, revision, homePage,
-		namespace, sourceCodeRepository, publishedTime, licenses,
-		externalConnectionName, repositoryName, originType)
+	licenses := []string{}
+	for _, license := range packageVersionDesc.Licenses {
+		licenses = append(licenses, aws.ToString(license.Name))
+	}
+
+	pkgDetails = PackageVersionDetail{
+		Package: packageName,
+		Namespace: aws.ToString(packageVersionDesc.Namespace),
+		DisplayName: aws.ToString(packageVersionDesc.DisplayName),
+		Version: packageVersion,
+		Revision: aws.ToString(packageVersionDesc.Revision),
+		Status: string(packageVersionDesc.Status),
+		HomePage: aws.ToString(packageVersionDesc.HomePage),
+		SourceCodeRepository: aws.ToString(packageVersionDesc.SourceCodeRepository),
+		PublishedTime: aws.ToTime(packageVersionDesc.PublishedTime),
+		Licenses: licenses,
+		Origin: awsPackageOrigin(packageVersionDesc.Origin),
+	}
 	
     //
-    return pkgDetailsStr, nil
+    return pkgDetails, nil
+}
+
+/**
+ * AWS CodeArticat (CA) - Assets (files) of a given version of a package,
+ * with their hashes
+ * References:
+ *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/codeartifact/api_op_ListPackageVersionAssets.go
+ *   + https://docs.aws.amazon.com/codeartifact/latest/APIReference/API_AssetSummary.html
+ *
+*/
+func AWSCodeArtifactListPackageVersionAssets(ctx context.Context,
+	awsConfig aws.Config,
+	domainName string, domainOwner string, repoName string,
+	repoFormat awscatypes.PackageFormat, namespace string, packageName string,
+	packageVersion string, opts ListOptions,
+	fn func(PackageAsset) error) error {
+	// Using the Config value, create the CodeArtifact client
+	svc := codeartifact.NewFromConfig(awsConfig)
+
+	// Build the request with its input parameters
+	params := &codeartifact.ListPackageVersionAssetsInput{
+		Domain: aws.String(domainName),
+		DomainOwner: aws.String(domainOwner),
+		Format: repoFormat,
+		Repository: aws.String(repoName),
+		Namespace: awsPackageNamespace(repoFormat, namespace),
+		Package: aws.String(packageName),
+		PackageVersion: aws.String(packageVersion),
+		MaxResults: opts.pageSize(),
+	}
+
+	//
+	paginator := codeartifact.NewListPackageVersionAssetsPaginator(svc, params)
+	return listPages(ctx, opts, paginator.HasMorePages,
+		func(ctx context.Context) ([]PackageAsset, error) {
+			resp, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, awsError("codeartifact", "ListPackageVersionAssets",
+					err)
+			}
+			assets := []PackageAsset{}
+			for _, assetStruct := range resp.Assets {
+				assets = append(assets, PackageAsset{
+					Name: aws.ToString(assetStruct.Name),
+					Size: aws.ToInt64(assetStruct.Size),
+					Hashes: assetStruct.Hashes,
+				})
+			}
+			return assets, nil
+		}, fn)
+}
+
+/**
+ * AWS CodeArticat (CA) - Dependencies declared by a given version
+ * of a package (e.g., in the metadata of a wheel or in a POM file)
+ * References:
+ *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/codeartifact/api_op_ListPackageVersionDependencies.go
+ *   + https://docs.aws.amazon.com/codeartifact/latest/APIReference/API_PackageDependency.html
+ *
+*/
+func AWSCodeArtifactListPackageVersionDependencies(ctx context.Context,
+	awsConfig aws.Config,
+	domainName string, domainOwner string, repoName string,
+	repoFormat awscatypes.PackageFormat, namespace string, packageName string,
+	packageVersion string, fn func(PackageDependency) error) error {
+	// Using the Config value, create the CodeArtifact client
+	svc := codeartifact.NewFromConfig(awsConfig)
+
+	// Build the request with its input parameters
+	params := &codeartifact.ListPackageVersionDependenciesInput{
+		Domain: aws.String(domainName),
+		DomainOwner: aws.String(domainOwner),
+		Format: repoFormat,
+		Repository: aws.String(repoName),
+		Namespace: awsPackageNamespace(repoFormat, namespace),
+		Package: aws.String(packageName),
+		PackageVersion: aws.String(packageVersion),
+	}
+
+	// The SDK provides no paginator for that operation: the pages
+	// are retrieved from the continuation tokens
+	morePages := true
+	return listPages(ctx, ListOptions{}, func() bool { return morePages },
+		func(ctx context.Context) ([]PackageDependency, error) {
+			resp, err := svc.ListPackageVersionDependencies(ctx, params)
+			if err != nil {
+				return nil, awsError("codeartifact",
+					"ListPackageVersionDependencies", err)
+			}
+			params.NextToken = resp.NextToken
+			morePages = aws.ToString(resp.NextToken) != ""
+
+			dependencies := []PackageDependency{}
+			for _, dependencyStruct := range resp.Dependencies {
+				dependencies = append(dependencies, PackageDependency{
+					Package: aws.ToString(dependencyStruct.Package),
+					Namespace: aws.ToString(dependencyStruct.Namespace),
+					DependencyType: aws.ToString(dependencyStruct.DependencyType),
+					VersionRequirement: aws.ToString(dependencyStruct.VersionRequirement),
+				})
+			}
+			return dependencies, nil
+		}, fn)
+}
+
+/**
+ * AWS CodeArticat (CA) - Copy of versions of a package from a repository
+ * to another one. Both repositories have to belong to the same domain,
+ * which may however be shared with other accounts. The versions are
+ * given either by version or by version and revision (versionRevisions,
+ * used when not empty). An existing version of the destination repository
+ * is overwritten only when allowed, the copy of that version failing
+ * otherwise. The versions are copied without their upstream repositories
+ * References:
+ *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/codeartifact/api_op_CopyPackageVersions.go
+ *   + https://docs.aws.amazon.com/codeartifact/latest/APIReference/API_CopyPackageVersions.html
+ *
+*/
+func AWSCodeArtifactCopyPackageVersions(ctx context.Context,
+	awsConfig aws.Config,
+	domainName string, domainOwner string, sourceRepoName string,
+	destinationRepoName string, repoFormat awscatypes.PackageFormat,
+	namespace string, packageName string, versionRevisions map[string]string,
+	versions []string, allowOverwrite bool) ([]PackageVersion, error) {
+	pkgVersions := []PackageVersion{}
+
+	// Using the Config value, create the CodeArtifact client
+	svc := codeartifact.NewFromConfig(awsConfig)
+
+	// Build the request with its input parameters. Versions and
+	// VersionRevisions are mutually exclusive
+	params := &codeartifact.CopyPackageVersionsInput{
+		Domain: aws.String(domainName),
+		DomainOwner: aws.String(domainOwner),
+		SourceRepository: aws.String(sourceRepoName),
+		DestinationRepository: aws.String(destinationRepoName),
+		Format: repoFormat,
+		Namespace: awsPackageNamespace(repoFormat, namespace),
+		Package: aws.String(packageName),
+		AllowOverwrite: aws.Bool(allowOverwrite),
+		IncludeFromUpstream: aws.Bool(false),
+	}
+	if len(versionRevisions) > 0 {
+		params.VersionRevisions = versionRevisions
+	} else {
+		params.Versions = versions
+	}
+	resp, err := svc.CopyPackageVersions(ctx, params)
+	if err != nil {
+		return pkgVersions, awsError("codeartifact", "CopyPackageVersions", err)
+	}
+
+	//
+	return awsCopiedVersions(packageName, versions, resp)
+}
+
+/**
+ * AWS CodeArticat (CA) - Versions copied by CopyPackageVersions. The copy
+ * of every version may fail on its own (e.g., when the version already
+ * exists in the destination repository), and a version which is neither
+ * reported as copied nor as failed is not taken for copied
+ */
+func awsCopiedVersions(packageName string, versions []string,
+	resp *codeartifact.CopyPackageVersionsOutput) ([]PackageVersion, error) {
+	pkgVersions := []PackageVersion{}
+	failures := []error{}
+	for _, version := range versions {
+		if versionError, failed := resp.FailedVersions[version]; failed {
+			var kind error
+			if versionError.ErrorCode == awscatypes.PackageVersionErrorCodeNotFound {
+				kind = ErrNotFound
+			}
+			failures = append(failures, &Error{Service: "codeartifact",
+				Operation: "CopyPackageVersions", Kind: kind,
+				Err: fmt.Errorf("version %s: %s: %s", version,
+					versionError.ErrorCode,
+					aws.ToString(versionError.ErrorMessage))})
+			continue
+		}
+		versionInfo, copied := resp.SuccessfulVersions[version]
+		if !copied {
+			failures = append(failures, &Error{Service: "codeartifact",
+				Operation: "CopyPackageVersions",
+				Err: fmt.Errorf("version %s: neither copied nor failed, according to the response",
+					version)})
+			continue
+		}
+		pkgVersions = append(pkgVersions, PackageVersion{
+			Package: packageName,
+			Version: version,
+			Revision: aws.ToString(versionInfo.Revision),
+			Status: string(versionInfo.Status),
+		})
+	}
+	if len(failures) > 0 {
+		return pkgVersions, errors.Join(failures...)
+	}
+	return pkgVersions, nil
+}
+
+/**
+ * AWS CodeArticat (CA) - Origin of a versioned package, which may be
+ * missing from the responses of the API
+*/
+func awsPackageOrigin(origin *awscatypes.PackageVersionOrigin) PackageOrigin {
+	pkgOrigin := PackageOrigin{}
+	if origin == nil {
+		return pkgOrigin
+	}
+
+	pkgOrigin.OriginType = string(origin.OriginType)
+	if origin.DomainEntryPoint != nil {
+		pkgOrigin.ExternalConnectionName = aws.ToString(origin.DomainEntryPoint.ExternalConnectionName)
+		pkgOrigin.RepositoryName = aws.ToString(origin.DomainEntryPoint.RepositoryName)
+	}
+	return pkgOrigin
+}
+
+/**
+ * AWS CodeArticat (CA) - Authorization token of a domain, i.e., the password
+ * of the native clients (e.g., pip, twine, mvn), as given by
+ * `aws codeartifact get-authorization-token`. The token is valid for
+ * the lifetime of the credentials (at most 12 hours)
+ * References:
+ *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/codeartifact/api_op_GetAuthorizationToken.go
+ *   + https://docs.aws.amazon.com/codeartifact/latest/ug/tokens-authentication.html
+ *
+*/
+func AWSCodeArtifactGetAuthorizationToken(ctx context.Context,
+	awsConfig aws.Config, domainName string,
+	domainOwner string) (string, time.Time, error) {
+	// Using the Config value, create the CodeArtifact client
+	svc := codeartifact.NewFromConfig(awsConfig)
+
+	// Build the request with its input parameters. A zero duration gives
+	// the same expiration as the credentials
+	params := &codeartifact.GetAuthorizationTokenInput{
+		Domain: aws.String(domainName),
+		DomainOwner: aws.String(domainOwner),
+		DurationSeconds: aws.Int64(0),
+	}
+	resp, err := svc.GetAuthorizationToken(ctx, params)
+	if err != nil {
+		return "", time.Time{}, awsError("codeartifact",
+			"GetAuthorizationToken", err)
+	}
+
+	//
+	return aws.ToString(resp.AuthorizationToken), aws.ToTime(resp.Expiration),
+		nil
+}
+
+/**
+ * AWS CodeArticat (CA) - Endpoint (URL) of a repository for a given format,
+ * to which the native clients (e.g., twine) upload the packages
+ * References:
+ *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/codeartifact/api_op_GetRepositoryEndpoint.go
+ *   + https://docs.aws.amazon.com/codeartifact/latest/ug/python-configure-twine.html
+ *
+*/
+func AWSCodeArtifactGetRepositoryEndpoint(ctx context.Context,
+	awsConfig aws.Config, domainName string, domainOwner string,
+	repoName string, repoFormat awscatypes.PackageFormat) (string, error) {
+	// Using the Config value, create the CodeArtifact client
+	svc := codeartifact.NewFromConfig(awsConfig)
+
+	// Build the request with its input parameters
+	params := &codeartifact.GetRepositoryEndpointInput{
+		Domain: aws.String(domainName),
+		DomainOwner: aws.String(domainOwner),
+		Repository: aws.String(repoName),
+		Format: repoFormat,
+	}
+	resp, err := svc.GetRepositoryEndpoint(ctx, params)
+	if err != nil {
+		return "", awsError("codeartifact", "GetRepositoryEndpoint", err)
+	}
+
+	//
+	return aws.ToString(resp.RepositoryEndpoint), nil
 }
 
 /**
@@ -281,113 +683,251 @@ func AWSCodeArtifactDescribePackageVersion(domainName string,
  *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/ecr/api_op_DescribeRepositories.go
  *   + https://docs.aws.amazon.com/AmazonECR/latest/APIReference/API_Repository.html
 */
-func AWSECRListRepositories() ([]string, error) {
-    messages := []string {}
-
-    // Using the Config value, create the ECR client
-    svc := ecr.NewFromConfig(awsConfig)
-
-    // Build the request with its input parameters
-	params := &ecr.DescribeRepositoriesInput{}
-    resp, err := svc.DescribeRepositories(context.TODO(), params)
-    if err != nil {
-        log.Fatalf("failed to list repositories, %v", err)
-    }
+func AWSECRListRepositories(ctx context.Context, awsConfig aws.Config,
+	opts ListOptions, fn func(string) error) error {
+	// Using the Config value, create the ECR client
+	svc := ecr.NewFromConfig(awsConfig)
+
+	// Build the request with its input parameters
+	params := &ecr.DescribeRepositoriesInput{
+		MaxResults: opts.pageSize(),
+	}
 
 	//
-    for _, repository := range resp.Repositories {
-		repoName := aws.ToString(repository.RepositoryName)
-		repoUri := aws.ToString(repository.RepositoryUri)
-		createdAt := repository.CreatedAt
-		imageTagMutability := repository.ImageTagMutability
-		registryArn := aws.ToString(repository.RepositoryArn)
-		message := fmt.Sprintf("Name=%s Created-at=%s Image-tag-mutability=%s repoUri=%s Registry-arn=%s",
-			repoName, createdAt, imageTagMutability, repoUri, registryArn)
-		messages = append(messages, message)
-    }
-
-    //
-    return messages, nil
+	paginator := ecr.NewDescribeRepositoriesPaginator(svc, params)
+	return listPages(ctx, opts, paginator.HasMorePages,
+		func(ctx context.Context) ([]string, error) {
+			resp, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, awsError("ecr", "DescribeRepositories", err)
+			}
+			messages := []string{}
+			for _, repository := range resp.Repositories {
+				repoName := aws.ToString(repository.RepositoryName)
+				repoUri := aws.ToString(repository.RepositoryUri)
+				createdAt := repository.CreatedAt
+				imageTagMutability := repository.ImageTagMutability
+				registryArn := aws.ToString(repository.RepositoryArn)
+				messages = append(messages, fmt.Sprintf("Name=%s Created-at=%s Image-tag-mutability=%s repoUri=%s Registry-arn=%s",
+					repoName, createdAt, imageTagMutability, repoUri, registryArn))
+			}
+			return messages, nil
+		}, fn)
 }
 
 /**
- * AWS Elastic Container Registry (ECR) - List of images
+ * AWS Elastic Container Registry (ECR) - List of images, optionally
+ * only those having a given tag status (TAGGED, UNTAGGED or ANY)
  * References:   
  *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/ecr/types/types.go
  *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/ecr/api_op_ListImages.go
  *   + https://docs.aws.amazon.com/AmazonECR/latest/APIReference/API_ImageIdentifier.html
 */
-func AWSECRListImages(repoName string) ([]string, error) {
-    messages := []string {}
-
-    // Using the Config value, create the ECR client
-    svc := ecr.NewFromConfig(awsConfig)
+func AWSECRListImages(ctx context.Context, awsConfig aws.Config,
+	repoName string, opts ListOptions, fn func(ImageID) error) error {
+	// Using the Config value, create the ECR client
+	svc := ecr.NewFromConfig(awsConfig)
 
-    // Build the request with its input parameters
+	// Build the request with its input parameters
 	params := &ecr.ListImagesInput{
 		RepositoryName: aws.String(repoName),
+		MaxResults: opts.pageSize(),
+	}
+	if opts.TagStatus != "" {
+		params.Filter = &ecrtypes.ListImagesFilter{
+			TagStatus: ecrtypes.TagStatus(opts.TagStatus),
+		}
 	}
-    resp, err := svc.ListImages(context.TODO(), params)
-    if err != nil {
-        log.Fatalf("failed to list domains, %v", err)
-    }
 
 	//
-    for _, image := range resp.ImageIds {
-		imageTag := aws.ToString(image.ImageTag)
-		imageDigest := aws.ToString(image.ImageDigest)
-		message := fmt.Sprintf("Image-tag=%s Image-digest=%s",
-			imageTag, imageDigest)
-		messages = append(messages, message)
-    }
-
-    //
-    return messages, nil
+	paginator := ecr.NewListImagesPaginator(svc, params)
+	return listPages(ctx, opts, paginator.HasMorePages,
+		func(ctx context.Context) ([]ImageID, error) {
+			resp, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, awsError("ecr", "ListImages", err)
+			}
+			imageIds := []ImageID{}
+			for _, image := range resp.ImageIds {
+				imageIds = append(imageIds, ImageID{
+					Tag: aws.ToString(image.ImageTag),
+					Digest: aws.ToString(image.ImageDigest),
+				})
+			}
+			return imageIds, nil
+		}, fn)
 }
 
 /**
- * AWS Elastic Container Registry (ECR) - List of images
+ * AWS Elastic Container Registry (ECR) - Details of the images, optionally
+ * only those having a given tag status (TAGGED, UNTAGGED or ANY)
  * References:   
  *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/ecr/types/types.go
  *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/ecr/api_op_DescribeImages.go
  *   + https://docs.aws.amazon.com/AmazonECR/latest/APIReference/API_ImageDetail.html
 */
-func AWSECRDescribeImages(repoName string) ([]string, error) {
-    messages := []string {}
+func AWSECRDescribeImages(ctx context.Context, awsConfig aws.Config,
+	repoName string, opts ListOptions, fn func(ImageDetail) error) error {
+	// Using the Config value, create the ECR client
+	svc := ecr.NewFromConfig(awsConfig)
 
-    // Using the Config value, create the ECR client
-    svc := ecr.NewFromConfig(awsConfig)
+	// Build the request with its input parameters
+	params := &ecr.DescribeImagesInput{
+		RepositoryName: aws.String(repoName),
+		MaxResults: opts.pageSize(),
+	}
+	if opts.TagStatus != "" {
+		params.Filter = &ecrtypes.DescribeImagesFilter{
+			TagStatus: ecrtypes.TagStatus(opts.TagStatus),
+		}
+	}
 
-    // Build the request with its input parameters
+	//
+	paginator := ecr.NewDescribeImagesPaginator(svc, params)
+	return listPages(ctx, opts, paginator.HasMorePages,
+		func(ctx context.Context) ([]ImageDetail, error) {
+			resp, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, awsError("ecr", "DescribeImages", err)
+			}
+			images := []ImageDetail{}
+			for _, image := range resp.ImageDetails {
+				images = append(images, awsImageDetail(image))
+			}
+			return images, nil
+		}, fn)
+}
+
+/**
+ * AWS Elastic Container Registry (ECR) - Details of an image, in which
+ * the scan status and findings may be missing
+*/
+func awsImageDetail(image ecrtypes.ImageDetail) ImageDetail {
+	imageDetail := ImageDetail{
+		Tags: image.ImageTags,
+		Digest: aws.ToString(image.ImageDigest),
+		PushedAt: aws.ToTime(image.ImagePushedAt),
+		SizeInBytes: aws.ToInt64(image.ImageSizeInBytes),
+		LastRecordedPullTime: aws.ToTime(image.LastRecordedPullTime),
+		ArtifactMediaType: aws.ToString(image.ArtifactMediaType),
+		ManifestMediaType: aws.ToString(image.ImageManifestMediaType),
+	}
+	if image.ImageScanStatus != nil {
+		imageDetail.ScanStatus = string(image.ImageScanStatus.Status)
+	}
+	if image.ImageScanFindingsSummary != nil {
+		imageDetail.ScanFindings = image.ImageScanFindingsSummary.FindingSeverityCounts
+	}
+	return imageDetail
+}
+
+/**
+ * AWS Elastic Container Registry (ECR) - Details of the image for a given tag
+ * References:
+ *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/ecr/api_op_DescribeImages.go
+ *   + https://docs.aws.amazon.com/AmazonECR/latest/APIReference/API_ImageIdentifier.html
+*/
+func AWSECRDescribeImageTag(ctx context.Context, awsConfig aws.Config,
+	repoName string, imageTag string) (ImageDetail, error) {
+	var imageDetail ImageDetail
+
+	// Using the Config value, create the ECR client
+	svc := ecr.NewFromConfig(awsConfig)
+
+	// Build the request with its input parameters
 	params := &ecr.DescribeImagesInput{
 		RepositoryName: aws.String(repoName),
+		ImageIds: []ecrtypes.ImageIdentifier{
+			{ImageTag: aws.String(imageTag)},
+		},
 	}
-    resp, err := svc.DescribeImages(context.TODO(), params)
-    if err != nil {
-        log.Fatalf("failed to list domains, %v", err)
-    }
+	resp, err := svc.DescribeImages(ctx, params)
+	if err != nil {
+		return imageDetail, awsError("ecr", "DescribeImages", err)
+	}
+	if len(resp.ImageDetails) == 0 {
+		errMsg := fmt.Sprintf("no image tagged %s in the %s ECR repository",
+			imageTag, repoName)
+		return imageDetail, &Error{Service: "ecr",
+			Operation: "DescribeImages", Kind: ErrNotFound,
+			Err: errors.New(errMsg)}
+	}
+	imageDetail = awsImageDetail(resp.ImageDetails[0])
 
 	//
-    for _, image := range resp.ImageDetails {
-		imageTags := image.ImageTags
-		imageDigest := aws.ToString(image.ImageDigest)
-		imagePushedAt := image.ImagePushedAt
-		imageSizeInBytes := image.ImageSizeInBytes
-		lastRecordedPullTime := image.LastRecordedPullTime
-		artifactMediaType := aws.ToString(image.ArtifactMediaType)
-		imageManifestMediaType := aws.ToString(image.ImageManifestMediaType)
-		imageScanStatus := image.ImageScanStatus
-		imageScanFindingsSummary := image.ImageScanFindingsSummary
-		message := fmt.Sprintf("Image-tags=%s Image-digest=%s Image-pushed-at=%s Image-size-in-bytes=%s Artifact-media-type=%s	Last-recorded-pull-time=%s Image-manifest-media-type=%s Image-scan-status=%s Image-scan-findings-summary=%s",
-			imageTags, imageDigest, imagePushedAt, imageSizeInBytes,
-			artifactMediaType,
-			lastRecordedPullTime, imageManifestMediaType,
-			imageScanStatus, imageScanFindingsSummary)
-		messages = append(messages, message)
-    }
+	return imageDetail, nil
+}
 
-    //
-    return messages, nil
+/**
+ * AWS Elastic MapReduce (EMR) - Details of an active cluster, given its name
+ * References:
+ *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/emr/api_op_ListClusters.go
+ *   + https://github.com/aws/aws-sdk-go-v2/blob/main/service/emr/api_op_DescribeCluster.go
+ *   + https://docs.aws.amazon.com/emr/latest/APIReference/API_Cluster.html
+*/
+func AWSEMRDescribeCluster(ctx context.Context, awsConfig aws.Config,
+	clusterName string) (ClusterDetail, error) {
+	var clusterDetail ClusterDetail
+
+	//
+	if clusterName == "" {
+		return clusterDetail, invalidInputError("emr", "ListClusters",
+			"empty EMR cluster name")
+	}
+
+	// Using the Config value, create the EMR client
+	svc := emr.NewFromConfig(awsConfig)
+
+	// Only the active clusters are considered, as several terminated
+	// clusters may share the same name
+	params := &emr.ListClustersInput{
+		ClusterStates: []emrtypes.ClusterState{
+			emrtypes.ClusterStateStarting,
+			emrtypes.ClusterStateBootstrapping,
+			emrtypes.ClusterStateRunning,
+			emrtypes.ClusterStateWaiting,
+		},
+	}
+	clusterId := ""
+	paginator := emr.NewListClustersPaginator(svc, params)
+	for clusterId == "" && paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			return clusterDetail, awsError("emr", "ListClusters", err)
+		}
+		for _, cluster := range resp.Clusters {
+			if aws.ToString(cluster.Name) == clusterName {
+				clusterId = aws.ToString(cluster.Id)
+				break
+			}
+		}
+	}
+	if clusterId == "" {
+		errMsg := fmt.Sprintf("no active EMR cluster named %s", clusterName)
+		return clusterDetail, &Error{Service: "emr",
+			Operation: "ListClusters", Kind: ErrNotFound,
+			Err: errors.New(errMsg)}
+	}
+
+	// Details of the cluster
+	resp, err := svc.DescribeCluster(ctx,
+		&emr.DescribeClusterInput{ClusterId: aws.String(clusterId)})
+	if err != nil {
+		return clusterDetail, awsError("emr", "DescribeCluster", err)
+	}
+
+	cluster := resp.Cluster
+	clusterDetail = ClusterDetail{
+		Id: clusterId,
+		Name: clusterName,
+		ReleaseLabel: aws.ToString(cluster.ReleaseLabel),
+	}
+	if cluster.Status != nil {
+		clusterDetail.State = string(cluster.Status.State)
+	}
+
+	//
+	return clusterDetail, nil
 }
 
 /**
@@ -398,8 +938,8 @@ func AWSECRDescribeImages(repoName string) ([]string, error) {
  *   + https://github.com/aws/smithy-go/blob/main/middleware/metadata.go
  *
 */
-func AWSAirflowCreateLoginToken(environment string) (string, string,
-	middleware.Metadata, error) {
+func AWSAirflowCreateLoginToken(ctx context.Context, awsConfig aws.Config,
+	environment string) (string, string, middleware.Metadata, error) {
 	cliToken := ""
 	webServerHostname := ""
 	var resultMetadata middleware.Metadata
@@ -407,7 +947,8 @@ func AWSAirflowCreateLoginToken(environment string) (string, string,
     //
     if environment == "" {
         return webServerHostname, cliToken, resultMetadata,
-			errors.New("empty Airflow/MWAA environment")
+			invalidInputError("mwaa", "CreateCliToken",
+				"empty Airflow/MWAA environment")
     }
 
     // Create an Amazon MWAA (managed Airflow service) client
@@ -417,9 +958,10 @@ func AWSAirflowCreateLoginToken(environment string) (string, string,
 	params := &mwaa.CreateCliTokenInput{
 		Name: aws.String(environment),
 	}
-    output, err := svc.CreateCliToken(context.TODO(), params)
+    output, err := svc.CreateCliToken(ctx, params)
     if err != nil {
-		log.Fatal(err)
+		return webServerHostname, cliToken, resultMetadata,
+			awsError("mwaa", "CreateCliToken", err)
     }
 
 	webServerHostname = aws.ToString(output.WebServerHostname)
@@ -457,20 +999,22 @@ func AWSAirflowCreateLoginToken(environment string) (string, string,
  * + GitHub - AWS - Sample code for MWAA - Bash operator script:
  *   https://github.com/aws-samples/amazon-mwaa-examples/tree/main/dags/bash_operator_script
 */
-func AWSAirflowCLI(webServerHostname string, cliToken string,
-	command string) (string, error) {
+func AWSAirflowCLI(ctx context.Context, webServerHostname string,
+	cliToken string, command string) (string, error) {
 	stdoutStr := ""
 	
     //
     if command == "" {
-        return stdoutStr, errors.New("empty MWAA CLI command")
+        return stdoutStr, invalidInputError("mwaa", "CLI",
+			"empty MWAA CLI command")
     }
 
 	api_url := fmt.Sprintf("https://%s/aws_mwaa/cli", webServerHostname)
-	body := []byte(fmt.Sprintf(command))
-    request, err := http.NewRequest("POST", api_url, bytes.NewBuffer(body))
+	body := []byte(command)
+    request, err := http.NewRequestWithContext(ctx, "POST", api_url, bytes.NewBuffer(body))
     if err != nil {
-		log.Fatal(err)
+		return stdoutStr, &Error{Service: "mwaa", Operation: "CLI",
+			Kind: ErrInvalidInput, Err: err}
     }
 
 	// Add the headers
@@ -484,26 +1028,36 @@ func AWSAirflowCLI(webServerHostname string, cliToken string,
 	client := &http.Client{}
 	response, err := client.Do(request)
 	if err != nil {
-		log.Fatal(err)
+		return stdoutStr, &Error{Service: "mwaa", Operation: "CLI", Err: err}
 	}
 
 	defer response.Body.Close()
 
 	responseData, err := ioutil.ReadAll(response.Body)
     if err != nil {
-        log.Fatal(err)
+		return stdoutStr, &Error{Service: "mwaa", Operation: "CLI", Err: err}
     }
+	if response.StatusCode != http.StatusOK {
+		errMsg := fmt.Sprintf("HTTP status %s: %s", response.Status,
+			string(responseData))
+		return stdoutStr, &Error{Service: "mwaa", Operation: "CLI",
+			Kind: classifyHTTPStatus(response.StatusCode),
+			Err: errors.New(errMsg)}
+	}
 	//log.Println("MWAA response data: ", string(responseData))
 
 	// Map the HTTP reponse onto a MWAAResponse structure
 	var mwaaResponseObject MWAAResponse
-	json.Unmarshal(responseData, &mwaaResponseObject)
+	err = json.Unmarshal(responseData, &mwaaResponseObject)
+	if err != nil {
+		return stdoutStr, &Error{Service: "mwaa", Operation: "CLI", Err: err}
+	}
 	stdoutB64Str := mwaaResponseObject.StdOut
 
 	// Base64 decode the `stdout` string
 	stdoutData, err := base64.StdEncoding.DecodeString(stdoutB64Str)
 	if err != nil {
-		log.Fatal("error:", err)
+		return stdoutStr, &Error{Service: "mwaa", Operation: "CLI", Err: err}
 	}
 
 	stdoutStr = string(stdoutData)
@@ -0,0 +1,258 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/service/aws_provider.go
+//
+package service
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+// Implementation of the provider interfaces on top of the AWS services
+// (S3, CodeArtifact, ECR, MWAA, EMR)
+type awsProvider struct{}
+
+func init() {
+	RegisterProvider("aws", awsProvider{})
+}
+
+// Every section gets its own AWS configuration, built from its region
+// and, when specified, from the IAM role to be assumed
+func (awsProvider) CallerIdentity(ctx context.Context,
+	cfg SectionConfig) (CallerIdentity, error) {
+	awsConfig, err := AWSConfig(ctx, cfg.Region, cfg.RoleArn)
+	if err != nil {
+		return CallerIdentity{}, err
+	}
+	return AWSGetCallerIdentity(ctx, awsConfig)
+}
+
+func (awsProvider) ObjectStorage(ctx context.Context,
+	cfg SectionConfig) (ObjectStorage, error) {
+	awsConfig, err := AWSConfig(ctx, cfg.Region, cfg.RoleArn)
+	if err != nil {
+		return nil, err
+	}
+	return awsObjectStorage{awsConfig}, nil
+}
+
+func (awsProvider) ArtifactRepository(ctx context.Context,
+	cfg SectionConfig) (ArtifactRepository, error) {
+	awsConfig, err := AWSConfig(ctx, cfg.Region, cfg.RoleArn)
+	if err != nil {
+		return nil, err
+	}
+	return awsArtifactRepository{awsConfig}, nil
+}
+
+func (awsProvider) ContainerRegistry(ctx context.Context,
+	cfg SectionConfig) (ContainerRegistry, error) {
+	awsConfig, err := AWSConfig(ctx, cfg.Region, cfg.RoleArn)
+	if err != nil {
+		return nil, err
+	}
+	return awsContainerRegistry{awsConfig}, nil
+}
+
+func (awsProvider) Orchestrator(ctx context.Context,
+	cfg SectionConfig) (Orchestrator, error) {
+	awsConfig, err := AWSConfig(ctx, cfg.Region, cfg.RoleArn)
+	if err != nil {
+		return nil, err
+	}
+	return awsOrchestrator{awsConfig}, nil
+}
+
+func (awsProvider) ComputeEngine(ctx context.Context,
+	cfg SectionConfig) (ComputeEngine, error) {
+	awsConfig, err := AWSConfig(ctx, cfg.Region, cfg.RoleArn)
+	if err != nil {
+		return nil, err
+	}
+	return awsComputeEngine{awsConfig}, nil
+}
+
+/**
+ * AWS S3
+ */
+type awsObjectStorage struct {
+	awsConfig aws.Config
+}
+
+func (s awsObjectStorage) List(ctx context.Context, bucketName string,
+	prefix string, opts ListOptions, fn func(S3Object) error) error {
+	return AWSS3List(ctx, s.awsConfig, bucketName, prefix, opts, fn)
+}
+
+func (s awsObjectStorage) Upload(ctx context.Context, bucketName string,
+	key string, filepath string) (string, error) {
+	return AWSS3Upload(ctx, s.awsConfig, bucketName, key, filepath)
+}
+
+/**
+ * AWS CodeArtifact
+ */
+type awsArtifactRepository struct {
+	awsConfig aws.Config
+}
+
+func (r awsArtifactRepository) ListPackageVersions(ctx context.Context,
+	domainName string, domainOwner string, repoName string, format string,
+	namespace string, packageName string,
+	opts ListOptions, fn func(PackageVersion) error) error {
+	caFormat, err := AWSCodeArtifactFormatFromString(format)
+	if err != nil {
+		return err
+	}
+	return AWSCodeArtifactListPackageVersions(ctx, r.awsConfig, domainName,
+		domainOwner, repoName, caFormat, namespace, packageName, opts, fn)
+}
+
+func (r awsArtifactRepository) DescribePackageVersion(ctx context.Context,
+	domainName string, domainOwner string, repoName string, format string,
+	namespace string, packageName string,
+	packageVersion string) (PackageVersionDetail, error) {
+	caFormat, err := AWSCodeArtifactFormatFromString(format)
+	if err != nil {
+		return PackageVersionDetail{}, err
+	}
+	return AWSCodeArtifactDescribePackageVersion(ctx, r.awsConfig, domainName,
+		domainOwner, repoName, caFormat, namespace, packageName, packageVersion)
+}
+
+func (r awsArtifactRepository) ListPackageVersionAssets(ctx context.Context,
+	domainName string, domainOwner string, repoName string, format string,
+	namespace string, packageName string,
+	packageVersion string, opts ListOptions, fn func(PackageAsset) error) error {
+	caFormat, err := AWSCodeArtifactFormatFromString(format)
+	if err != nil {
+		return err
+	}
+	return AWSCodeArtifactListPackageVersionAssets(ctx, r.awsConfig, domainName,
+		domainOwner, repoName, caFormat, namespace, packageName, packageVersion,
+		opts, fn)
+}
+
+func (r awsArtifactRepository) ListPackageVersionDependencies(ctx context.Context,
+	domainName string, domainOwner string, repoName string, format string,
+	namespace string, packageName string,
+	packageVersion string, fn func(PackageDependency) error) error {
+	caFormat, err := AWSCodeArtifactFormatFromString(format)
+	if err != nil {
+		return err
+	}
+	return AWSCodeArtifactListPackageVersionDependencies(ctx, r.awsConfig,
+		domainName, domainOwner, repoName, caFormat, namespace, packageName,
+		packageVersion, fn)
+}
+
+func (r awsArtifactRepository) CopyPackageVersion(ctx context.Context,
+	domainName string, domainOwner string, sourceRepoName string,
+	targetRepoName string, format string, namespace string,
+	packageName string, packageVersion string, revision string,
+	allowOverwrite bool) (PackageVersion, error) {
+	caFormat, err := AWSCodeArtifactFormatFromString(format)
+	if err != nil {
+		return PackageVersion{}, err
+	}
+	versionRevisions := map[string]string{}
+	if revision != "" {
+		versionRevisions[packageVersion] = revision
+	}
+	pkgVersions, err := AWSCodeArtifactCopyPackageVersions(ctx, r.awsConfig,
+		domainName, domainOwner, sourceRepoName, targetRepoName, caFormat,
+		namespace, packageName, versionRevisions, []string{packageVersion},
+		allowOverwrite)
+	if err != nil {
+		return PackageVersion{}, err
+	}
+	return pkgVersions[0], nil
+}
+
+// The user name of CodeArtifact is always `aws`, the password being
+// an authorization token of the domain
+const awsCodeArtifactUsername = "aws"
+
+func (r awsArtifactRepository) RepositoryEndpoint(ctx context.Context,
+	domainName string, domainOwner string, repoName string,
+	format string) (RepositoryEndpoint, error) {
+	caFormat, err := AWSCodeArtifactFormatFromString(format)
+	if err != nil {
+		return RepositoryEndpoint{}, err
+	}
+	url, err := AWSCodeArtifactGetRepositoryEndpoint(ctx, r.awsConfig,
+		domainName, domainOwner, repoName, caFormat)
+	if err != nil {
+		return RepositoryEndpoint{}, err
+	}
+	token, expiration, err := AWSCodeArtifactGetAuthorizationToken(ctx,
+		r.awsConfig, domainName, domainOwner)
+	if err != nil {
+		return RepositoryEndpoint{}, err
+	}
+	return RepositoryEndpoint{URL: url, Username: awsCodeArtifactUsername,
+		Password: token, Expiration: expiration}, nil
+}
+
+/**
+ * AWS Elastic Container Registry (ECR)
+ */
+type awsContainerRegistry struct {
+	awsConfig aws.Config
+}
+
+func (r awsContainerRegistry) ListImages(ctx context.Context, repoName string,
+	opts ListOptions, fn func(ImageID) error) error {
+	return AWSECRListImages(ctx, r.awsConfig, repoName, opts, fn)
+}
+
+func (r awsContainerRegistry) DescribeImages(ctx context.Context,
+	repoName string, opts ListOptions, fn func(ImageDetail) error) error {
+	return AWSECRDescribeImages(ctx, r.awsConfig, repoName, opts, fn)
+}
+
+func (r awsContainerRegistry) DescribeImageTag(ctx context.Context,
+	repoName string, imageTag string) (ImageDetail, error) {
+	return AWSECRDescribeImageTag(ctx, r.awsConfig, repoName, imageTag)
+}
+
+/**
+ * AWS Managed Workflows for Apache Airflow (MWAA)
+ */
+type awsOrchestrator struct {
+	awsConfig aws.Config
+}
+
+func (o awsOrchestrator) ListDags(ctx context.Context,
+	environment string) ([]utilities.MwaaDagMetadata, error) {
+	// Create a one-time MWAA CLI token
+	webServerHostname, cliToken, _,
+		err := AWSAirflowCreateLoginToken(ctx, o.awsConfig, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invoke the MWAA CLI API for the specific command (here, the list of DAGs)
+	command := "dags list -o json"
+	stdoutStr, err := AWSAirflowCLI(ctx, webServerHostname, cliToken, command)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the output when the command is "dags list -o json"
+	return utilities.ParseAWSMWAADagListOutput(stdoutStr)
+}
+
+/**
+ * AWS Elastic MapReduce (EMR)
+ */
+type awsComputeEngine struct {
+	awsConfig aws.Config
+}
+
+func (e awsComputeEngine) DescribeCluster(ctx context.Context,
+	clusterName string) (ClusterDetail, error) {
+	return AWSEMRDescribeCluster(ctx, e.awsConfig, clusterName)
+}
@@ -0,0 +1,105 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/service/aws_test.go
+//
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codeartifact"
+	awscatypes "github.com/aws/aws-sdk-go-v2/service/codeartifact/types"
+)
+
+/**
+ * Check that every CodeArtifact package format is known, and that
+ * an unknown one is rejected as an invalid input
+ */
+func TestAWSCodeArtifactFormatFromString(t *testing.T) {
+	formats := []string{"npm", "pypi", "maven", "nuget", "generic", "ruby",
+		"swift", "cargo"}
+	for _, format := range formats {
+		caFormat, err := AWSCodeArtifactFormatFromString(format)
+		if err != nil || string(caFormat) != format {
+			t.Errorf(`AWSCodeArtifactFormatFromString(%q) = %q, %v`,
+				format, caFormat, err)
+		}
+	}
+
+	for _, unknown := range []string{"", "PyPI", "conda"} {
+		_, err := AWSCodeArtifactFormatFromString(unknown)
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf(`AWSCodeArtifactFormatFromString(%q) = %v, expected an invalid input error`,
+				unknown, err)
+		}
+	}
+}
+
+/**
+ * Check that the leading @ of the npm scopes is trimmed, and that
+ * an empty namespace is not sent
+ */
+func TestAWSPackageNamespace(t *testing.T) {
+	tests := []struct {
+		format    awscatypes.PackageFormat
+		namespace string
+		expected  *string
+	}{
+		{awscatypes.PackageFormatNpm, "@myorg", aws.String("myorg")},
+		{awscatypes.PackageFormatNpm, "myorg", aws.String("myorg")},
+		{awscatypes.PackageFormatNpm, "@", nil},
+		{awscatypes.PackageFormatNpm, "", nil},
+		{awscatypes.PackageFormatMaven, "org.apache.spark",
+			aws.String("org.apache.spark")},
+		{awscatypes.PackageFormatGeneric, "@myorg", aws.String("@myorg")},
+		{awscatypes.PackageFormatPypi, "", nil},
+	}
+	for _, test := range tests {
+		namespace := awsPackageNamespace(test.format, test.namespace)
+		if (namespace == nil) != (test.expected == nil) ||
+			aws.ToString(namespace) != aws.ToString(test.expected) {
+			t.Errorf(`awsPackageNamespace(%q, %q) = %v, expected %v`,
+				test.format, test.namespace, aws.ToString(namespace),
+				aws.ToString(test.expected))
+		}
+	}
+}
+
+/**
+ * Check that the versions copied by CopyPackageVersions are the ones
+ * reported as successful, a version missing from both the successful
+ * and the failed ones being a failure as well
+ */
+func TestAWSCopiedVersions(t *testing.T) {
+	resp := &codeartifact.CopyPackageVersionsOutput{
+		SuccessfulVersions: map[string]awscatypes.SuccessfulPackageVersionInfo{
+			"0.0.1": {Revision: aws.String("REV1"),
+				Status: awscatypes.PackageVersionStatusPublished},
+		},
+		FailedVersions: map[string]awscatypes.PackageVersionError{
+			"0.0.2": {ErrorCode: awscatypes.PackageVersionErrorCodeNotFound,
+				ErrorMessage: aws.String("not found")},
+		},
+	}
+
+	pkgVersions, err := awsCopiedVersions("example-pkg", []string{"0.0.1"}, resp)
+	if err != nil || len(pkgVersions) != 1 ||
+		pkgVersions[0] != (PackageVersion{Package: "example-pkg",
+			Version: "0.0.1", Revision: "REV1", Status: "Published"}) {
+		t.Errorf(`awsCopiedVersions(0.0.1) = %+v, %v`, pkgVersions, err)
+	}
+
+	_, err = awsCopiedVersions("example-pkg", []string{"0.0.2"}, resp)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf(`awsCopiedVersions(0.0.2) = %v, expected a not found error`, err)
+	}
+
+	pkgVersions, err = awsCopiedVersions("example-pkg", []string{"0.0.3"}, resp)
+	if err == nil || len(pkgVersions) != 0 ||
+		!strings.Contains(err.Error(), "version 0.0.3: neither copied nor failed") {
+		t.Errorf(`awsCopiedVersions(0.0.3) = %+v, %v, expected a failure`,
+			pkgVersions, err)
+	}
+}
@@ -0,0 +1,128 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/service/errors.go
+//
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aws/smithy-go"
+)
+
+// Kinds of errors, which the callers may test with errors.Is()
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrAccessDenied = errors.New("access denied")
+	ErrThrottled    = errors.New("throttled")
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+/**
+ * Error returned by the functions of the service package. It wraps
+ * the underlying (e.g., AWS SDK) error, which therefore remains reachable
+ * with errors.As(), as well as its kind (when it could be classified),
+ * so that, for instance, errors.Is(err, service.ErrNotFound) holds
+ */
+type Error struct {
+	// Cloud service (e.g., s3, codeartifact)
+	Service string
+	// Operation/API call (e.g., ListObjectsV2)
+	Operation string
+	// One of the ErrXxx kinds above, or nil when not classified
+	Kind error
+	// Underlying error
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Kind == nil {
+		return fmt.Sprintf("%s %s: %v", e.Service, e.Operation, e.Err)
+	}
+	return fmt.Sprintf("%s %s: %v: %v", e.Service, e.Operation, e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+/**
+ * Error for an input (e.g., empty bucket name) rejected before any call
+ * to the cloud service
+ */
+func invalidInputError(serviceName string, operation string,
+	msg string) error {
+	return &Error{
+		Service:   serviceName,
+		Operation: operation,
+		Kind:      ErrInvalidInput,
+		Err:       errors.New(msg),
+	}
+}
+
+/**
+ * Wrap an error returned by the AWS SDK, classifying it from the AWS error
+ * code or, when there is no such code, from the HTTP status code
+ */
+func awsError(serviceName string, operation string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{
+		Service:   serviceName,
+		Operation: operation,
+		Kind:      classifyAWSError(err),
+		Err:       err,
+	}
+}
+
+func classifyAWSError(err error) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		code := apiErr.ErrorCode()
+		switch {
+		case strings.HasSuffix(code, "NotFoundException"),
+			strings.HasPrefix(code, "NoSuch"), code == "NotFound":
+			return ErrNotFound
+		case strings.HasPrefix(code, "AccessDenied"),
+			code == "UnauthorizedOperation", code == "ExpiredToken",
+			code == "InvalidClientTokenId",
+			code == "UnrecognizedClientException":
+			return ErrAccessDenied
+		case strings.HasPrefix(code, "Throttling"),
+			code == "TooManyRequestsException",
+			code == "RequestLimitExceeded", code == "SlowDown":
+			return ErrThrottled
+		case code == "ValidationException",
+			strings.HasPrefix(code, "InvalidParameter"),
+			code == "InvalidRequest":
+			return ErrInvalidInput
+		}
+	}
+
+	var httpErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &httpErr) {
+		return classifyHTTPStatus(httpErr.HTTPStatusCode())
+	}
+
+	return nil
+}
+
+func classifyHTTPStatus(statusCode int) error {
+	switch statusCode {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrAccessDenied
+	case http.StatusTooManyRequests:
+		return ErrThrottled
+	case http.StatusBadRequest:
+		return ErrInvalidInput
+	}
+	return nil
+}
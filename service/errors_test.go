@@ -0,0 +1,97 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/service/errors_test.go
+//
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscatypes "github.com/aws/aws-sdk-go-v2/service/codeartifact/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// Error of the SDK for an HTTP response without any AWS error code
+func httpResponseError(statusCode int) error {
+	return &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{
+			Response: &http.Response{StatusCode: statusCode}},
+		Err: errors.New("example error"),
+	}
+}
+
+/**
+ * Check that the AWS error codes and, without any code, the HTTP status
+ * codes are classified into the kinds of errors
+ */
+func TestClassifyAWSError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected error
+	}{
+		{&smithy.GenericAPIError{Code: "ResourceNotFoundException"}, ErrNotFound},
+		{&smithy.GenericAPIError{Code: "NoSuchBucket"}, ErrNotFound},
+		{&awscatypes.ResourceNotFoundException{Message: aws.String("example")},
+			ErrNotFound},
+		{&smithy.GenericAPIError{Code: "AccessDeniedException"}, ErrAccessDenied},
+		{&smithy.GenericAPIError{Code: "ExpiredToken"}, ErrAccessDenied},
+		{&smithy.GenericAPIError{Code: "ThrottlingException"}, ErrThrottled},
+		{&smithy.GenericAPIError{Code: "TooManyRequestsException"}, ErrThrottled},
+		{&smithy.GenericAPIError{Code: "ValidationException"}, ErrInvalidInput},
+		{&smithy.GenericAPIError{Code: "InvalidParameterValue"}, ErrInvalidInput},
+		{&smithy.GenericAPIError{Code: "InternalServerException"}, nil},
+		{httpResponseError(http.StatusNotFound), ErrNotFound},
+		{httpResponseError(http.StatusForbidden), ErrAccessDenied},
+		{httpResponseError(http.StatusTooManyRequests), ErrThrottled},
+		{httpResponseError(http.StatusBadRequest), ErrInvalidInput},
+		{httpResponseError(http.StatusInternalServerError), nil},
+		{errors.New("example error"), nil},
+	}
+	for _, test := range tests {
+		kind := classifyAWSError(fmt.Errorf("operation error: %w", test.err))
+		if kind != test.expected {
+			t.Errorf(`classifyAWSError(%v) = %v, expected %v`, test.err, kind,
+				test.expected)
+		}
+	}
+}
+
+/**
+ * Check that both the kind and the original SDK error of a wrapped error
+ * are reachable with errors.Is() and errors.As()
+ */
+func TestAWSError(t *testing.T) {
+	sdkErr := &awscatypes.ResourceNotFoundException{
+		Message: aws.String("example-pkg is not found")}
+	err := awsError("codeartifact", "DescribePackageVersion",
+		fmt.Errorf("operation error: %w", sdkErr))
+
+	if !errors.Is(err, ErrNotFound) || errors.Is(err, ErrAccessDenied) {
+		t.Errorf(`errors.Is(%v, ErrNotFound) = %t, expected only that kind`,
+			err, errors.Is(err, ErrNotFound))
+	}
+
+	var notFoundErr *awscatypes.ResourceNotFoundException
+	if !errors.As(err, &notFoundErr) || notFoundErr != sdkErr {
+		t.Errorf(`errors.As(%v) = %v, expected the SDK error`, err, notFoundErr)
+	}
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) ||
+		apiErr.ErrorCode() != "ResourceNotFoundException" {
+		t.Errorf(`errors.As(%v) does not reach the API error`, err)
+	}
+	var serviceErr *Error
+	if !errors.As(err, &serviceErr) || serviceErr.Service != "codeartifact" ||
+		serviceErr.Kind != ErrNotFound {
+		t.Errorf(`errors.As(%v) = %+v, expected the service error`, err,
+			serviceErr)
+	}
+
+	if awsError("codeartifact", "DescribePackageVersion", nil) != nil {
+		t.Errorf(`awsError(nil) is not nil`)
+	}
+}
@@ -0,0 +1,82 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/service/list.go
+//
+package service
+
+import (
+	"context"
+	"errors"
+)
+
+// Options of the listing calls. The results are retrieved page by page
+// (following the continuation tokens of the services) and handed over,
+// one item at a time, to a callback as soon as their page has arrived
+type ListOptions struct {
+	// Maximum number of items to be handed over (0 means no limit)
+	Limit int
+	// Number of items requested per page (0 means the default of the service)
+	PageSize int32
+	// Only the package versions having that status (e.g., Published)
+	Status string
+	// Only the images having that tag status (TAGGED, UNTAGGED or ANY)
+	TagStatus string
+}
+
+// Returned by a listing callback in order to stop the listing without
+// any error, in the same way as fs.SkipAll
+var ErrStopListing = errors.New("stop listing")
+
+// Whether the given number of handed over items has reached the limit
+func (opts ListOptions) limitReached(count int) bool {
+	return opts.Limit > 0 && count >= opts.Limit
+}
+
+// Page size for the paginators of the AWS SDK, which expect a pointer
+// for some services
+func (opts ListOptions) pageSize() *int32 {
+	if opts.PageSize <= 0 {
+		return nil
+	}
+	pageSize := opts.PageSize
+	return &pageSize
+}
+
+// Error of a listing callback, ErrStopListing meaning a normal end
+func callbackError(err error) error {
+	if errors.Is(err, ErrStopListing) {
+		return nil
+	}
+	return err
+}
+
+/**
+ * Hand over the items of a listing to the callback, page after page,
+ * until the last page, the limit of the options or ErrStopListing. No
+ * further page is retrieved once the listing has stopped.
+ * The pages are given by the caller (e.g., from the HasMorePages and
+ * NextPage methods of a paginator of the AWS SDK), which converts
+ * their items and wraps the errors of the service
+ */
+func listPages[T any](ctx context.Context, opts ListOptions,
+	hasMorePages func() bool,
+	nextPage func(ctx context.Context) ([]T, error),
+	fn func(T) error) error {
+	count := 0
+	for hasMorePages() {
+		items, err := nextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if err := fn(item); err != nil {
+				return callbackError(err)
+			}
+			count++
+			if opts.limitReached(count) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/service/list_test.go
+//
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// Listing of fake pages, recording the number of pages retrieved and
+// the items handed over. The page of index failingPage, if any, fails
+type fakeListing struct {
+	pages       [][]int
+	failingPage int
+	retrieved   int
+	items       []int
+}
+
+func (l *fakeListing) list(opts ListOptions, fn func(int) error) error {
+	return listPages(context.Background(), opts,
+		func() bool { return l.retrieved < len(l.pages) },
+		func(ctx context.Context) ([]int, error) {
+			l.retrieved++
+			if l.retrieved-1 == l.failingPage {
+				return nil, errors.New("page error")
+			}
+			return l.pages[l.retrieved-1], nil
+		},
+		func(item int) error {
+			l.items = append(l.items, item)
+			return fn(item)
+		})
+}
+
+/**
+ * Check that the items of the pages are handed over until the last
+ * page, the limit or ErrStopListing, without retrieving further pages
+ */
+func TestListPages(t *testing.T) {
+	pages := [][]int{{1, 2, 3}, {4, 5}, {}, {6}}
+	handOver := func(item int) error { return nil }
+	stopAt := func(last int) func(int) error {
+		return func(item int) error {
+			if item == last {
+				return fmt.Errorf("enough items: %w", ErrStopListing)
+			}
+			return nil
+		}
+	}
+	tests := []struct {
+		name          string
+		opts          ListOptions
+		failingPage   int
+		fn            func(int) error
+		expectedItems string
+		expectedPages int
+		expectedErr   string
+	}{
+		{"all the pages", ListOptions{}, -1, handOver, "[1 2 3 4 5 6]", 4, ""},
+		{"limit within a page", ListOptions{Limit: 4}, -1, handOver,
+			"[1 2 3 4]", 2, ""},
+		{"limit at the end of a page", ListOptions{Limit: 3}, -1, handOver,
+			"[1 2 3]", 1, ""},
+		{"limit above the number of items", ListOptions{Limit: 10}, -1,
+			handOver, "[1 2 3 4 5 6]", 4, ""},
+		{"stopped by the callback", ListOptions{}, -1, stopAt(2), "[1 2]", 1,
+			""},
+		{"callback error", ListOptions{}, -1,
+			func(item int) error {
+				if item == 4 {
+					return errors.New("callback error")
+				}
+				return nil
+			}, "[1 2 3 4]", 2, "callback error"},
+		{"page error", ListOptions{}, 1, handOver, "[1 2 3]", 2, "page error"},
+	}
+	for _, test := range tests {
+		listing := &fakeListing{pages: pages, failingPage: test.failingPage}
+		err := listing.list(test.opts, test.fn)
+		if fmt.Sprint(listing.items) != test.expectedItems ||
+			listing.retrieved != test.expectedPages ||
+			fmt.Sprint(err) != fmt.Sprint(errorOrNil(test.expectedErr)) {
+			t.Errorf(`listPages(%s) = %v, %v, with %d page(s) retrieved, expected %s, %q, with %d page(s)`,
+				test.name, listing.items, err, listing.retrieved,
+				test.expectedItems, test.expectedErr, test.expectedPages)
+		}
+	}
+}
+
+func errorOrNil(msg string) error {
+	if msg == "" {
+		return nil
+	}
+	return errors.New(msg)
+}
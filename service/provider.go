@@ -0,0 +1,202 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/service/provider.go
+//
+package service
+
+import (
+	"context"
+
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+// Location, on a given cloud provider, of a section of the deployment
+// specification (e.g., `storage_container`, `artifact_repo`)
+type SectionConfig struct {
+	Provider  string
+	Region    string
+	AccountId string
+	// Optional IAM role to be assumed (e.g., for a cross-account access)
+	RoleArn string
+}
+
+// Identity of the caller (e.g., AWS IAM user or role), and the account
+// it belongs to
+type CallerIdentity struct {
+	UserId  string `json:"user_id" yaml:"user_id"`
+	Account string `json:"account" yaml:"account"`
+	Arn     string `json:"arn" yaml:"arn"`
+}
+
+func (identity CallerIdentity) String() string {
+	return fmt.Sprintf("UserId=%s Account=%s Arn=%s",
+		identity.UserId, identity.Account, identity.Arn)
+}
+
+// Object storage (e.g., AWS S3, Azure Data Storage, GCS)
+type ObjectStorage interface {
+	List(ctx context.Context, bucketName string, prefix string,
+		opts ListOptions, fn func(S3Object) error) error
+	Upload(ctx context.Context, bucketName string, key string,
+		filepath string) (string, error)
+}
+
+// Repository for the software artifacts (e.g., AWS CodeArtifact)
+type ArtifactRepository interface {
+	ListPackageVersions(ctx context.Context, domainName string,
+		domainOwner string, repoName string, format string, namespace string,
+		packageName string,
+		opts ListOptions, fn func(PackageVersion) error) error
+	DescribePackageVersion(ctx context.Context, domainName string,
+		domainOwner string, repoName string, format string, namespace string,
+		packageName string,
+		packageVersion string) (PackageVersionDetail, error)
+	ListPackageVersionAssets(ctx context.Context, domainName string,
+		domainOwner string, repoName string, format string, namespace string,
+		packageName string,
+		packageVersion string, opts ListOptions,
+		fn func(PackageAsset) error) error
+	ListPackageVersionDependencies(ctx context.Context, domainName string,
+		domainOwner string, repoName string, format string, namespace string,
+		packageName string,
+		packageVersion string, fn func(PackageDependency) error) error
+	// Copy of a version (in a given revision, if not empty) of a package
+	// from a repository to another one of the same domain
+	CopyPackageVersion(ctx context.Context, domainName string,
+		domainOwner string, sourceRepoName string, targetRepoName string,
+		format string, namespace string, packageName string,
+		packageVersion string, revision string,
+		allowOverwrite bool) (PackageVersion, error)
+	// Endpoint, and its credentials, to which the packages of a given
+	// format are published (e.g., with the PyPI upload protocol)
+	RepositoryEndpoint(ctx context.Context, domainName string,
+		domainOwner string, repoName string,
+		format string) (RepositoryEndpoint, error)
+}
+
+// Registry for the OCI (e.g., Docker) container images (e.g., AWS ECR)
+type ContainerRegistry interface {
+	ListImages(ctx context.Context, repoName string, opts ListOptions,
+		fn func(ImageID) error) error
+	DescribeImages(ctx context.Context, repoName string, opts ListOptions,
+		fn func(ImageDetail) error) error
+	DescribeImageTag(ctx context.Context, repoName string,
+		imageTag string) (ImageDetail, error)
+}
+
+// Workflow orchestrator (e.g., AWS MWAA)
+type Orchestrator interface {
+	ListDags(ctx context.Context,
+		environment string) ([]utilities.MwaaDagMetadata, error)
+}
+
+// Compute engine (e.g., Spark on AWS EMR, Spark on DataBricks)
+type ComputeEngine interface {
+	DescribeCluster(ctx context.Context,
+		clusterName string) (ClusterDetail, error)
+}
+
+// A cloud provider gives access to the implementations of the services
+// above, for a given section of the deployment specification
+type Provider interface {
+	// Effective identity, i.e., after the IAM role, if any, has been assumed
+	CallerIdentity(ctx context.Context,
+		cfg SectionConfig) (CallerIdentity, error)
+	ObjectStorage(ctx context.Context,
+		cfg SectionConfig) (ObjectStorage, error)
+	ArtifactRepository(ctx context.Context,
+		cfg SectionConfig) (ArtifactRepository, error)
+	ContainerRegistry(ctx context.Context,
+		cfg SectionConfig) (ContainerRegistry, error)
+	Orchestrator(ctx context.Context,
+		cfg SectionConfig) (Orchestrator, error)
+	ComputeEngine(ctx context.Context,
+		cfg SectionConfig) (ComputeEngine, error)
+}
+
+// Registry of the providers, indexed by the name used for the `provider`
+// field of the deployment specification
+var providers = map[string]Provider{}
+
+/**
+ * Register a provider, so that it may be selected from the deployment
+ * specification. Registering twice the same name is a programming error
+ */
+func RegisterProvider(name string, provider Provider) {
+	if _, exists := providers[name]; exists {
+		panic(fmt.Sprintf("the %s provider is already registered", name))
+	}
+	providers[name] = provider
+}
+
+/**
+ * Retrieve the provider registered under the given name
+ */
+func GetProvider(name string) (Provider, error) {
+	provider, found := providers[name]
+	if !found {
+		return nil, fmt.Errorf("the %q provider is not known (known providers: %s)",
+			name, strings.Join(ProviderNames(), ", "))
+	}
+	return provider, nil
+}
+
+/**
+ * Sorted list of the names of the registered providers
+ */
+func ProviderNames() []string {
+	names := []string{}
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func NewObjectStorage(ctx context.Context,
+	cfg SectionConfig) (ObjectStorage, error) {
+	provider, err := GetProvider(cfg.Provider)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ObjectStorage(ctx, cfg)
+}
+
+func NewArtifactRepository(ctx context.Context,
+	cfg SectionConfig) (ArtifactRepository, error) {
+	provider, err := GetProvider(cfg.Provider)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ArtifactRepository(ctx, cfg)
+}
+
+func NewContainerRegistry(ctx context.Context,
+	cfg SectionConfig) (ContainerRegistry, error) {
+	provider, err := GetProvider(cfg.Provider)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ContainerRegistry(ctx, cfg)
+}
+
+func NewOrchestrator(ctx context.Context,
+	cfg SectionConfig) (Orchestrator, error) {
+	provider, err := GetProvider(cfg.Provider)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Orchestrator(ctx, cfg)
+}
+
+func NewComputeEngine(ctx context.Context,
+	cfg SectionConfig) (ComputeEngine, error) {
+	provider, err := GetProvider(cfg.Provider)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ComputeEngine(ctx, cfg)
+}
@@ -0,0 +1,179 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/service/pypi.go
+//
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Format of the Python packages within the artifact repositories
+const PackageFormatPyPI = "pypi"
+
+// Types of the Python distributions, as named by the PyPI upload protocol
+const (
+	PyPIFiletypeWheel = "bdist_wheel"
+	PyPIFiletypeSdist = "sdist"
+)
+
+// Version of the core metadata declared for the uploaded distributions
+const pypiMetadataVersion = "2.1"
+
+var pypiNameSeparatorRegex = regexp.MustCompile(`[-_.]+`)
+
+// Distribution (wheel or sdist) of a Python package, as built into
+// a `dist/` directory (e.g., by `python -m build`)
+type PyPIDistribution struct {
+	Filepath string `json:"filepath" yaml:"filepath"`
+	Name     string `json:"name" yaml:"name"`
+	Version  string `json:"version" yaml:"version"`
+	Filetype string `json:"filetype" yaml:"filetype"`
+	// Python tag of a wheel (e.g., py3), `source` for a sdist
+	PyVersion string `json:"pyversion" yaml:"pyversion"`
+}
+
+// Normalized name of a Python package (PEP 503), e.g., induction-spark-basic
+// for Induction_Spark.basic, so that the names of the distributions may be
+// compared to the names of the modules
+func NormalizePyPIName(name string) string {
+	return strings.ToLower(pypiNameSeparatorRegex.ReplaceAllString(name, "-"))
+}
+
+/**
+ * Python distribution described by the name of a file:
+ *   + wheel: {name}-{version}(-{build})?-{python}-{abi}-{platform}.whl
+ *   + sdist: {name}-{version}.tar.gz (or .zip)
+ * The second returned value is false when the file is not a distribution
+ * (e.g., a README file lying in the `dist/` directory)
+ * Reference: https://packaging.python.org/en/latest/specifications/binary-distribution-format/
+ */
+func ParsePyPIDistribution(distFilepath string) (PyPIDistribution, bool,
+	error) {
+	dist := PyPIDistribution{Filepath: distFilepath}
+	filename := filepath.Base(distFilepath)
+
+	switch {
+	case strings.HasSuffix(filename, ".whl"):
+		parts := strings.Split(strings.TrimSuffix(filename, ".whl"), "-")
+		if len(parts) != 5 && len(parts) != 6 {
+			return dist, true, fmt.Errorf("the name of the %s wheel does not follow the {name}-{version}(-{build})?-{python}-{abi}-{platform}.whl convention",
+				filename)
+		}
+		dist.Name, dist.Version = parts[0], parts[1]
+		dist.Filetype, dist.PyVersion = PyPIFiletypeWheel, parts[len(parts)-3]
+	case strings.HasSuffix(filename, ".tar.gz"),
+		strings.HasSuffix(filename, ".zip"):
+		stem := strings.TrimSuffix(strings.TrimSuffix(filename, ".tar.gz"),
+			".zip")
+		idx := strings.LastIndex(stem, "-")
+		if idx <= 0 || idx == len(stem)-1 {
+			return dist, true, fmt.Errorf("the name of the %s sdist does not follow the {name}-{version}.tar.gz convention",
+				filename)
+		}
+		dist.Name, dist.Version = stem[:idx], stem[idx+1:]
+		dist.Filetype, dist.PyVersion = PyPIFiletypeSdist, "source"
+	default:
+		return dist, false, nil
+	}
+	return dist, true, nil
+}
+
+/**
+ * Upload a Python distribution onto a repository with the legacy PyPI
+ * upload protocol (the one of `twine upload`): a multipart form, posted
+ * onto the endpoint of the repository with a basic authentication,
+ * carrying the metadata and the MD5 and SHA-256 digests of the file.
+ * The uploaded file is returned as an asset, with its SHA-256 hash
+ * Reference: https://warehouse.pypa.io/api-reference/legacy.html#upload-api
+ */
+func PyPIUpload(ctx context.Context, endpoint RepositoryEndpoint,
+	dist PyPIDistribution) (PackageAsset, error) {
+	asset := PackageAsset{Name: filepath.Base(dist.Filepath)}
+	if endpoint.URL == "" {
+		return asset, invalidInputError("pypi", "Upload",
+			"empty repository endpoint")
+	}
+
+	content, err := os.ReadFile(dist.Filepath)
+	if err != nil {
+		return asset, err
+	}
+	md5Sum := md5.Sum(content)
+	sha256Sum := sha256.Sum256(content)
+	asset.Size = int64(len(content))
+	asset.Hashes = map[string]string{
+		AssetHashSHA256: hex.EncodeToString(sha256Sum[:]),
+	}
+
+	// Multipart form: the metadata fields, then the content of the file
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	fields := [][2]string{
+		{":action", "file_upload"},
+		{"protocol_version", "1"},
+		{"metadata_version", pypiMetadataVersion},
+		{"name", dist.Name},
+		{"version", dist.Version},
+		{"filetype", dist.Filetype},
+		{"pyversion", dist.PyVersion},
+		{"md5_digest", hex.EncodeToString(md5Sum[:])},
+		{"sha256_digest", asset.Hashes[AssetHashSHA256]},
+	}
+	for _, field := range fields {
+		err = form.WriteField(field[0], field[1])
+		if err != nil {
+			return asset, err
+		}
+	}
+	part, err := form.CreateFormFile("content", asset.Name)
+	if err != nil {
+		return asset, err
+	}
+	_, err = part.Write(content)
+	if err != nil {
+		return asset, err
+	}
+	err = form.Close()
+	if err != nil {
+		return asset, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		endpoint.URL, &body)
+	if err != nil {
+		return asset, &Error{Service: "pypi", Operation: "Upload",
+			Kind: ErrInvalidInput, Err: err}
+	}
+	request.Header.Set("Content-Type", form.FormDataContentType())
+	request.SetBasicAuth(endpoint.Username, endpoint.Password)
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return asset, &Error{Service: "pypi", Operation: "Upload", Err: err}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK &&
+		response.StatusCode != http.StatusCreated {
+		responseData, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+		errMsg := fmt.Sprintf("HTTP status %s: %s", response.Status,
+			strings.TrimSpace(string(responseData)))
+		return asset, &Error{Service: "pypi", Operation: "Upload",
+			Kind: classifyHTTPStatus(response.StatusCode),
+			Err:  errors.New(errMsg)}
+	}
+	return asset, nil
+}
@@ -0,0 +1,115 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/service/types.go
+//
+package service
+
+import (
+	"time"
+)
+
+// Object within a storage container (e.g., AWS S3 bucket)
+type S3Object struct {
+	Key          string    `json:"key" yaml:"key"`
+	Size         int64     `json:"size" yaml:"size"`
+	ETag         string    `json:"etag" yaml:"etag"`
+	LastModified time.Time `json:"last_modified" yaml:"last_modified"`
+	StorageClass string    `json:"storage_class" yaml:"storage_class"`
+}
+
+// Origin of a versioned package within an artifact repository
+// (e.g., published directly or fetched through an external connection)
+type PackageOrigin struct {
+	ExternalConnectionName string `json:"external_connection_name" yaml:"external_connection_name"`
+	RepositoryName         string `json:"repository_name" yaml:"repository_name"`
+	OriginType             string `json:"origin_type" yaml:"origin_type"`
+}
+
+// Status of the versions of a package which may be installed
+const PackageVersionStatusPublished = "Published"
+
+// Summary of a version of a package within an artifact repository
+// (e.g., AWS CodeArtifact)
+type PackageVersion struct {
+	Package  string `json:"package" yaml:"package"`
+	Version  string `json:"version" yaml:"version"`
+	Revision string `json:"revision" yaml:"revision"`
+	// Published, Unfinished, Unlisted, Archived, Disposed or Deleted
+	Status string        `json:"status" yaml:"status"`
+	Origin PackageOrigin `json:"origin" yaml:"origin"`
+}
+
+// Details of a version of a package within an artifact repository
+type PackageVersionDetail struct {
+	Package              string        `json:"package" yaml:"package"`
+	Namespace            string        `json:"namespace" yaml:"namespace"`
+	DisplayName          string        `json:"display_name" yaml:"display_name"`
+	Version              string        `json:"version" yaml:"version"`
+	Revision             string        `json:"revision" yaml:"revision"`
+	Status               string        `json:"status" yaml:"status"`
+	HomePage             string        `json:"home_page" yaml:"home_page"`
+	SourceCodeRepository string        `json:"source_code_repository" yaml:"source_code_repository"`
+	PublishedTime        time.Time     `json:"published_time" yaml:"published_time"`
+	Licenses             []string      `json:"licenses" yaml:"licenses"`
+	Origin               PackageOrigin `json:"origin" yaml:"origin"`
+}
+
+// Hash algorithm of the assets, as named by AWS CodeArtifact
+const AssetHashSHA256 = "SHA-256"
+
+// Asset (file, e.g., wheel or jar) of a version of a package
+type PackageAsset struct {
+	Name string `json:"name" yaml:"name"`
+	Size int64  `json:"size" yaml:"size"`
+	// Hashes of the content (e.g., SHA-256), indexed by algorithm
+	Hashes map[string]string `json:"hashes" yaml:"hashes"`
+}
+
+// Dependency declared by a version of a package (e.g., the Requires-Dist
+// of a Python package, the dependencies of the POM of a Maven artifact)
+type PackageDependency struct {
+	Package   string `json:"package" yaml:"package"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// Type of dependency, depending on the format (e.g., compile or test
+	// for Maven, dev or regular for npm)
+	DependencyType string `json:"dependency_type" yaml:"dependency_type"`
+	// Specifier of the required versions (e.g., ==3.3.0, >=3.3,<3.4)
+	VersionRequirement string `json:"version_requirement" yaml:"version_requirement"`
+}
+
+// Endpoint of a repository for the native clients of a format (e.g., twine
+// for pypi), with its credentials. The password (an authorization token)
+// is never reported
+type RepositoryEndpoint struct {
+	URL        string    `json:"url" yaml:"url"`
+	Username   string    `json:"username" yaml:"username"`
+	Password   string    `json:"-" yaml:"-"`
+	Expiration time.Time `json:"expiration" yaml:"expiration"`
+}
+
+// Identifier of an image within a container registry (e.g., AWS ECR)
+type ImageID struct {
+	Tag    string `json:"tag" yaml:"tag"`
+	Digest string `json:"digest" yaml:"digest"`
+}
+
+// Details of an image within a container registry
+type ImageDetail struct {
+	Tags                 []string  `json:"tags" yaml:"tags"`
+	Digest               string    `json:"digest" yaml:"digest"`
+	PushedAt             time.Time `json:"pushed_at" yaml:"pushed_at"`
+	SizeInBytes          int64     `json:"size_in_bytes" yaml:"size_in_bytes"`
+	LastRecordedPullTime time.Time `json:"last_recorded_pull_time" yaml:"last_recorded_pull_time"`
+	ArtifactMediaType    string    `json:"artifact_media_type" yaml:"artifact_media_type"`
+	ManifestMediaType    string    `json:"manifest_media_type" yaml:"manifest_media_type"`
+	ScanStatus           string    `json:"scan_status" yaml:"scan_status"`
+	// Number of findings of the last scan, indexed by severity
+	ScanFindings map[string]int32 `json:"scan_findings" yaml:"scan_findings"`
+}
+
+// Details of a cluster of a compute engine (e.g., AWS EMR)
+type ClusterDetail struct {
+	Id           string `json:"id" yaml:"id"`
+	Name         string `json:"name" yaml:"name"`
+	ReleaseLabel string `json:"release_label" yaml:"release_label"`
+	State        string `json:"state" yaml:"state"`
+}
@@ -0,0 +1,279 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/compatibility.go
+//
+package utilities
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Compatibility table, embedded into the binary. It is updated
+// by editing compatibility.yaml
+//
+//go:embed compatibility.yaml
+var compatibilityContent []byte
+
+// Compatibility table between the versions of Spark, of Delta Lake and
+// of the compute engines (see compatibility.yaml)
+type CompatibilityTable struct {
+	// Names of the packages giving the version of Spark and of Delta Lake
+	Packages struct {
+		Spark []string `yaml:"spark"`
+		Delta []string `yaml:"delta"`
+	} `yaml:"packages"`
+	// AWS EMR release -> version of Spark
+	EMR map[string]string `yaml:"emr"`
+	// Databricks runtime (major.minor) -> version of Spark
+	Databricks map[string]string `yaml:"databricks"`
+	// Delta Lake (major.minor) -> version of Spark (major.minor)
+	Delta map[string]string `yaml:"delta"`
+}
+
+// Kinds of compute engine, for which the version of Spark is known
+const (
+	ComputeEngineEMR        = "emr"
+	ComputeEngineDatabricks = "databricks"
+)
+
+// Kind of compute engine of a provider, when not specified
+// (`compute_engine.engine`)
+var defaultComputeEngines = map[string]string{
+	"aws": ComputeEngineEMR,
+}
+
+// Names of the compute engines, as displayed in the validation errors
+var computeEngineNames = map[string]string{
+	ComputeEngineEMR:        "AWS EMR",
+	ComputeEngineDatabricks: "Databricks runtime",
+}
+
+var (
+	majorMinorRegex  = regexp.MustCompile(`^(\d+)\.(\d+)`)
+	scalaSuffixRegex = regexp.MustCompile(`_2\.\d+$`)
+)
+
+// Formats of the releases of the compute engines (`compute_engine.cluster.version`),
+// along with an example: an EMR release label, with or without its `emr-`
+// prefix, or a Databricks runtime, with or without its `.x-scalaN` suffix
+// (e.g., 13.3.x-photon-scala2.12)
+var engineReleaseFormats = map[string]struct {
+	Regex   *regexp.Regexp
+	Example string
+}{
+	ComputeEngineEMR: {regexp.MustCompile(`^(emr-)?\d+\.\d+\.\d+$`),
+		"emr-6.9.0 or 6.9.0"},
+	ComputeEngineDatabricks: {regexp.MustCompile(`^\d+\.\d+(\.x(-[a-z]+)*-scala\d+\.\d+)?$`),
+		"13.3.x-scala2.12 or 13.3"},
+}
+
+// The embedded table is part of the binary: failing to parse it is
+// a bug, caught by the tests
+var compatibilityTable = loadCompatibilityTable()
+
+func loadCompatibilityTable() CompatibilityTable {
+	table := CompatibilityTable{}
+	err := yaml.Unmarshal(compatibilityContent, &table)
+	if err != nil {
+		panic(fmt.Sprintf("the embedded compatibility table is invalid: %v", err))
+	}
+	return table
+}
+
+// Major and minor numbers of a version, e.g., 3.3 for 3.3.0, or 12.2
+// for the 12.2.x-scala2.12 Databricks runtime. An empty string when
+// the version does not start with numbers
+func majorMinor(version string) string {
+	match := majorMinorRegex.FindStringSubmatch(version)
+	if match == nil {
+		return ""
+	}
+	return match[1] + "." + match[2]
+}
+
+// Version of Spark of a release of a compute engine, if known
+func (table CompatibilityTable) EngineSparkVersion(engine string,
+	release string) (string, bool) {
+	var sparkVersion string
+	var found bool
+	switch engine {
+	case ComputeEngineEMR:
+		sparkVersion, found = table.EMR[strings.TrimPrefix(release, "emr-")]
+	case ComputeEngineDatabricks:
+		sparkVersion, found = table.Databricks[majorMinor(release)]
+	}
+	return sparkVersion, found
+}
+
+// Version of Spark (major.minor) a version of Delta Lake is built for,
+// if known
+func (table CompatibilityTable) DeltaSparkVersion(deltaVersion string) (string,
+	bool) {
+	sparkVersion, found := table.Delta[majorMinor(deltaVersion)]
+	return sparkVersion, found
+}
+
+// Whether a package (e.g., pyspark, spark-sql_2.12) is one of the given
+// ones, the Scala suffix and the separators (`-` or `_`) being ignored
+func isPackageOf(name string, packageNames []string) bool {
+	normalize := func(name string) string {
+		name = scalaSuffixRegex.ReplaceAllString(strings.ToLower(name), "")
+		return strings.ReplaceAll(name, "_", "-")
+	}
+	for _, packageName := range packageNames {
+		if normalize(name) == normalize(packageName) {
+			return true
+		}
+	}
+	return false
+}
+
+// Node at a given path of mappings, if any
+func specNode(node *yaml.Node, keys ...string) *yaml.Node {
+	for _, key := range keys {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+		node = mappingValue(node, key)
+	}
+	return node
+}
+
+// Scalar value at a given path of mappings, if any
+func specValue(node *yaml.Node, keys ...string) string {
+	node = specNode(node, keys...)
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
+
+/**
+ * Check the compatibility of the versions of a specification once read
+ * (e.g., before a check or a deployment), in the same way as the `validate`
+ * command does (see validateCompatibility()). The errors, joined, have no
+ * position within the files, but the path of the offending value
+ */
+func CheckCompatibility(deplSpec SpecFile) error {
+	var root yaml.Node
+	err := root.Encode(deplSpec)
+	if err != nil {
+		return err
+	}
+
+	validator := &specValidator{}
+	validator.validateCompatibility(&root, compatibilityTable)
+	compatibilityErrors := []error{}
+	for _, validationError := range validator.errors {
+		compatibilityErrors = append(compatibilityErrors,
+			fmt.Errorf("%s: %s", validationError.Path, validationError.Message))
+	}
+	return errors.Join(compatibilityErrors...)
+}
+
+// Check of the format of a release, for a kind of compute engine.
+// The releases of the other kinds of engine are not checked
+func validateEngineRelease(engine string, release string) error {
+	format, found := engineReleaseFormats[engine]
+	if !found || format.Regex.MatchString(release) {
+		return nil
+	}
+	return fmt.Errorf("%q is not a release of the %s compute engine (e.g., %s)",
+		release, engine, format.Example)
+}
+
+/**
+ * Check that the release of the compute engine has the format of its
+ * kind of engine, and that the versions of Spark and of Delta Lake, given
+ * by the dependencies, fit together and with that release (e.g., EMR 6.9.0
+ * comes with Spark 3.3.0). The versions are compared on their major and
+ * minor numbers.
+ * A release unknown to the compatibility table is an error, unless
+ * the version of Spark of the release is given by the specification
+ * (`compute_engine.cluster.spark_version`), which then overrides the table.
+ * The versions of Delta Lake unknown to the table are not checked
+ */
+func (v *specValidator) validateCompatibility(root *yaml.Node,
+	table CompatibilityTable) {
+	// Version of Spark of the compute engine, if known
+	engineSpark, engineDesc := "", ""
+	if computeEngine := specNode(root, "compute_engine"); computeEngine != nil {
+		engine := specValue(computeEngine, "engine")
+		if engine == "" {
+			engine = defaultComputeEngines[specValue(computeEngine, "provider")]
+		}
+		release := specValue(computeEngine, "cluster", "version")
+		releaseNode := specNode(computeEngine, "cluster", "version")
+		overridden := specValue(computeEngine, "cluster", "spark_version")
+		sparkVersion, found := table.EngineSparkVersion(engine, release)
+		releaseErr := validateEngineRelease(engine, release)
+		switch {
+		case release == "" || computeEngineNames[engine] == "":
+			// No release, or a kind of engine unknown to the table
+		case releaseErr != nil:
+			v.addError(releaseNode, "compute_engine.cluster.version", "%v",
+				releaseErr)
+		case overridden != "":
+			engineSpark = overridden
+			engineDesc = fmt.Sprintf("the %s %s release (compute_engine.cluster.spark_version)",
+				computeEngineNames[engine], release)
+		case !found:
+			v.addError(releaseNode, "compute_engine.cluster.version",
+				"the %s %s release is not in the compatibility table of dppctl: give its version of Spark with compute_engine.cluster.spark_version (e.g., 3.3.0)",
+				computeEngineNames[engine], release)
+		default:
+			engineSpark = sparkVersion
+			engineDesc = fmt.Sprintf("the %s %s release",
+				computeEngineNames[engine], release)
+		}
+	}
+
+	dependencies := specNode(root, "container", "dependencies")
+	if dependencies == nil || dependencies.Kind != yaml.SequenceNode {
+		return
+	}
+
+	// Version of Spark, from the dependencies or, otherwise,
+	// from the compute engine
+	usedSpark, usedSparkDesc := engineSpark, engineDesc
+	for idx, dependency := range dependencies.Content {
+		name := specValue(dependency, "name")
+		versionNode := specNode(dependency, "version")
+		if !isPackageOf(name, table.Packages.Spark) || versionNode == nil ||
+			majorMinor(versionNode.Value) == "" {
+			continue
+		}
+
+		if engineSpark != "" &&
+			majorMinor(versionNode.Value) != majorMinor(engineSpark) {
+			v.addError(versionNode,
+				fmt.Sprintf("container.dependencies[%d].version", idx),
+				"%s %s does not match Spark %s of %s", name,
+				versionNode.Value, engineSpark, engineDesc)
+		}
+		usedSpark, usedSparkDesc = versionNode.Value, name
+	}
+
+	for idx, dependency := range dependencies.Content {
+		name := specValue(dependency, "name")
+		versionNode := specNode(dependency, "version")
+		if !isPackageOf(name, table.Packages.Delta) || versionNode == nil ||
+			usedSpark == "" {
+			continue
+		}
+
+		requiredSpark, found := table.DeltaSparkVersion(versionNode.Value)
+		if found && requiredSpark != majorMinor(usedSpark) {
+			v.addError(versionNode,
+				fmt.Sprintf("container.dependencies[%d].version", idx),
+				"%s %s requires Spark %s, whereas Spark %s is used (%s)",
+				name, versionNode.Value, requiredSpark, usedSpark,
+				usedSparkDesc)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/compatibility_test.go
+//
+package utilities
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+/**
+ * Check that the incompatible versions of Spark, Delta Lake and of
+ * the compute engine are reported, against the embedded table
+ */
+func TestCompatibility(t *testing.T) {
+	sample, err := os.ReadFile("../depl/aws-dev-sample.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		engine   string
+		release  string
+		override string
+		spark    string
+		delta    string
+		expected []string
+	}{
+		{"", "6.9.0", "", "3.3.0", "2.1.1", nil},
+		{"emr", "emr-6.9.0", "", "3.3.0", "2.1.1", nil},
+		{"", "6.9.0", "", "3.4.0", "2.4.0", []string{
+			"container.dependencies[0].version: pyspark 3.4.0 does not match Spark 3.3.0 of the AWS EMR 6.9.0 release"}},
+		{"", "6.9.0", "", "3.3.0", "2.4.0", []string{
+			"container.dependencies[1].version: delta-spark 2.4.0 requires Spark 3.4, whereas Spark 3.3.0 is used (pyspark)"}},
+		{"databricks", "13.3.x-scala2.12", "", "3.4.1", "2.4.0", nil},
+		{"databricks", "13.3.x-photon-scala2.12", "", "3.4.1", "2.4.0", nil},
+		{"databricks", "12.2", "", "3.4.1", "2.4.0", []string{
+			"container.dependencies[0].version: pyspark 3.4.1 does not match Spark 3.3.2 of the Databricks runtime 12.2 release"}},
+		{"", "13.3.x-scala2.12", "", "3.4.1", "2.4.0", []string{
+			`compute_engine.cluster.version: "13.3.x-scala2.12" is not a release of the emr compute engine (e.g., emr-6.9.0 or 6.9.0)`}},
+		{"databricks", "emr-6.9.0", "", "3.3.0", "2.1.1", []string{
+			`compute_engine.cluster.version: "emr-6.9.0" is not a release of the databricks compute engine (e.g., 13.3.x-scala2.12 or 13.3)`}},
+		// Release unknown to the table, unless its version of Spark is given
+		{"", "5.36.0", "", "3.3.0", "2.1.1", []string{
+			"compute_engine.cluster.version: the AWS EMR 5.36.0 release is not in the compatibility table of dppctl: give its version of Spark with compute_engine.cluster.spark_version (e.g., 3.3.0)"}},
+		{"", "9.9.0", "3.3.0", "3.3.0", "2.1.1", nil},
+		{"", "9.9.0", "3.5.0", "3.3.0", "2.1.1", []string{
+			"container.dependencies[0].version: pyspark 3.3.0 does not match Spark 3.5.0 of the AWS EMR 9.9.0 release (compute_engine.cluster.spark_version)"}},
+		// The version of Spark given overrides the table
+		{"", "6.9.0", "3.3.1", "3.3.0", "2.1.1", nil},
+	}
+	for _, test := range tests {
+		// The sample specification, with the versions of the test
+		cluster := fmt.Sprintf("  engine: %q\n  cluster:\n    name: example-cluster\n    version: %q",
+			test.engine, test.release)
+		if test.override != "" {
+			cluster += fmt.Sprintf("\n    spark_version: %q", test.override)
+		}
+		spec := strings.NewReplacer(
+			"      version: 3.3.0", "      version: "+test.spark,
+			"      version: 2.1.1", "      version: "+test.delta,
+			"  cluster:\n    name: example-cluster\n    version: 6.9.0", cluster,
+		).Replace(string(sample))
+		specFilepath := filepath.Join(t.TempDir(), "spec.yaml")
+		err = os.WriteFile(specFilepath, []byte(spec), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		validationErrors, err := ValidateSpecFile(specFilepath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		messages := []string{}
+		for _, validationError := range validationErrors {
+			messages = append(messages,
+				validationError.Path+": "+validationError.Message)
+		}
+
+		if fmt.Sprint(messages) != fmt.Sprint(append([]string{}, test.expected...)) {
+			t.Errorf(`ValidateSpecFile(%s %s) = %q, expected %q`,
+				test.engine, test.release, messages, test.expected)
+		}
+	}
+}
+
+/**
+ * Check that the versions of a specification, already read, are checked
+ * against the embedded table, the errors giving the path of the fields
+ */
+func TestCheckCompatibility(t *testing.T) {
+	deplSpec := SpecFile{}
+	deplSpec.ComputeEngine.Engine = "emr"
+	deplSpec.ComputeEngine.Cluster.Version = "emr-6.9.0"
+	deplSpec.Container.Dependencies = []Dependency{
+		{Name: "pyspark", Version: "3.3.0"},
+		{Name: "delta-spark", Version: "2.1.1"},
+	}
+	err := CheckCompatibility(deplSpec)
+	if err != nil {
+		t.Errorf(`CheckCompatibility() = %v, expected no error`, err)
+	}
+
+	// Delta Lake 2.4.0 fits Spark 3.4, which EMR 6.9.0 does not ship
+	deplSpec.Container.Dependencies[0].Version = "3.4.0"
+	deplSpec.Container.Dependencies[1].Version = "2.4.0"
+	expected := "container.dependencies[0].version: pyspark 3.4.0 does not match Spark 3.3.0 of the AWS EMR emr-6.9.0 release"
+	err = CheckCompatibility(deplSpec)
+	if err == nil || err.Error() != expected {
+		t.Errorf(`CheckCompatibility() = %v, expected %q`, err, expected)
+	}
+}
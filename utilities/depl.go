@@ -4,126 +4,217 @@
 package utilities
 
 import (
-    "log"
-	"io/ioutil"
-    "gopkg.in/yaml.v3"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
+// Location of a section of the deployment specification on a cloud provider.
+// When the optional IAM role is specified, it is assumed (e.g., through
+// AWS STS) before acting on the section, for instance for the artifact
+// repository to be in a shared tooling account while the rest lives
+// in the workload account. The fields may be inherited from the `defaults`
+// block of the specification
+type CloudLocation struct {
+	Provider string `yaml:"provider" validate:"required,inheritable" desc:"Cloud provider of the section" enum:"aws"`
+	Region string `yaml:"region" validate:"required,inheritable,region" desc:"Region of the cloud provider (e.g., eu-west-1)"`
+	AccountId string `yaml:"acct_id" validate:"required,inheritable,account_id" desc:"Account ID (12 digits) of the cloud provider, against which the caller identity is checked"`
+	RoleArn string `yaml:"role_arn,omitempty" validate:"inheritable" desc:"IAM role assumed before acting on the section (e.g., for a cross-account access)"`
+}
+
+// Module (package) to be deployed. It has to be published onto the artifact
+// repository and, when it ships an image, pushed onto the container
+// repository, tagged with the version of the module
+type Module struct {
+	Stack string `yaml:"stack" validate:"required" desc:"Technical stack of the module" enum:"python,scala,java"`
+	Name string `yaml:"name" validate:"required" desc:"Name of the package"`
+	Namespace string `yaml:"namespace,omitempty" desc:"Namespace of the package, if any (e.g., the groupId of a Maven artifact, the scope of an npm package)"`
+	Version string `yaml:"version" validate:"required,constraint" desc:"Version of the package, taken literally (e.g., 1.2.3 or 1.0-SNAPSHOT), or a constraint resolved against the artifact repository (e.g., ~0.0.1, '>=0.1,<0.2' or latest)"`
+	Format string `yaml:"format,omitempty" desc:"Format of the package within the artifact repository (by default, the format of the artifact repository)" enum:"npm,pypi,maven,nuget,generic,ruby,swift,cargo"`
+	Image string `yaml:"image,omitempty" desc:"Name of the container image (repository of the container registry) of the module, if any"`
+	// Version constraint, from which Version has been resolved (see
+	// workflow.ResolveVersions()). It is not read from the specification
+	Constraint string `yaml:"-"`
+}
+
+// Dependency (package) of the modules. The version has to be published
+// (i.e., neither missing, unlisted nor archived) on the artifact repository
+type Dependency struct {
+	Name string `yaml:"name" validate:"required" desc:"Name of the package (e.g., pyspark, delta-spark)"`
+	Namespace string `yaml:"namespace,omitempty" desc:"Namespace of the package, if any (e.g., the groupId of a Maven artifact, the scope of an npm package)"`
+	Version string `yaml:"version" validate:"required" desc:"Version of the package"`
+	Format string `yaml:"format,omitempty" desc:"Format of the package within the artifact repository (by default, the format of the artifact repository)" enum:"npm,pypi,maven,nuget,generic,ruby,swift,cargo"`
+}
+
+// Values inherited by every section (see CloudLocation), unless
+// the section overrides them
+type SpecDefaults struct {
+	Provider string `yaml:"provider,omitempty" desc:"Default cloud provider of the sections" enum:"aws"`
+	Region string `yaml:"region,omitempty" validate:"region" desc:"Default region of the sections (e.g., eu-west-1)"`
+	AccountId string `yaml:"acct_id,omitempty" validate:"account_id" desc:"Default account ID (12 digits) of the sections"`
+	RoleArn string `yaml:"role_arn,omitempty" desc:"Default IAM role assumed before acting on the sections"`
+}
+
+// The `validate` struct tags give the rules checked by ValidateSpec():
+// whether the field is required, and the format of its value, if any.
+// Along with the `enum` (known values) and `desc` (description) struct
+// tags, they are also translated into a JSON Schema (see GenerateSpecSchema())
 type SpecFile struct {
+	// Base specification file, onto which this file is overlaid (see
+	// SpecLayers). It is consumed when the layers are merged
+	Extends string `yaml:"extends,omitempty" desc:"Base specification file (relative to this file), onto which this file is overlaid"`
+
+	// Values inherited by the sections, applied when the layers are merged
+	Defaults SpecDefaults `yaml:"defaults,omitempty" desc:"Values (provider, region, acct_id, role_arn) inherited by every section, unless it overrides them"`
+
 	// Some meta-data for the project
 	Metadata struct {
-		Env string `yaml:"env"`
-		Project string `yaml:"project"`
-		GitUrl string `yaml:"git_url"`
-	} `yaml:"metadata"`
+		Env string `yaml:"env" validate:"required" desc:"Environment (e.g., dev, prod)"`
+		Project string `yaml:"project" validate:"required" desc:"Name of the project"`
+		GitUrl string `yaml:"git_url" desc:"URL of the Git repository of the project"`
+	} `yaml:"metadata" validate:"required" desc:"Meta-data of the project"`
 	
 	// Payload/workload: what has to be deployed
     Container struct {
-		Module struct {
-			Stack string `yaml:"stack"`
-			Name string `yaml:"name"`
-			Version string `yaml:"version"`
-		} `yaml:"module"`
+		Modules []Module `yaml:"modules" validate:"required" desc:"Modules (packages) to be deployed, e.g., several Python packages and a Scala job of the same pipeline"`
 
-		//
-		Dependencies struct {
-			Spark struct {
-				Version string `yaml:"version"`
-			} `yaml:"spark"`
-			DeltaSpark struct {
-				Version string `yaml:"version"`
-			} `yaml:"delta_spark"`
-		} `yaml:"dependencies"`
+		// Packages the modules depend on, which have to be available
+		// on the artifact repository
+		Dependencies []Dependency `yaml:"dependencies,omitempty" desc:"Dependencies of the modules, checked against the artifact repository"`
 		
-	} `yaml:"container"`
+	} `yaml:"container" validate:"required" desc:"Payload/workload: what has to be deployed"`
 	
 	// Details of the environment to be deployed
 
 	// Storage container (e.g., AWS S3 bucket, Azure Data Storage, GCS)
 	StorageContainer struct {
-		Provider string `yaml:"provider"`
-		Region string `yaml:"region"`
-		AccountId string `yaml:"acct_id"`
-		Name string `yaml:"name"`
-		Prefix string `yaml:"prefix"`
-	} `yaml:"storage_container"`
+		CloudLocation `yaml:",inline"`
+		Name string `yaml:"name" validate:"required,bucket" desc:"Name of the bucket"`
+		Prefix string `yaml:"prefix" desc:"Prefix (folder) within the bucket"`
+	} `yaml:"storage_container" validate:"required" desc:"Storage container (e.g., AWS S3 bucket)"`
 
 	// Repository for the software artifacts
 	ArtifactRepo struct {
-		Provider string `yaml:"provider"`
-		Region string `yaml:"region"`
-		AccountId string `yaml:"acct_id"`
-		Format string `yaml:"format"`
-		Domain string `yaml:"domain"`
-		Name string `yaml:"name"`
-	} `yaml:"artifact_repo"`
+		CloudLocation `yaml:",inline"`
+		Format string `yaml:"format" validate:"required" desc:"Format of the artifact repository" enum:"npm,pypi,maven,nuget,generic,ruby,swift,cargo"`
+		Domain string `yaml:"domain" validate:"required" desc:"Domain of the artifact repository"`
+		Name string `yaml:"name" validate:"required" desc:"Name of the artifact repository"`
+	} `yaml:"artifact_repo" validate:"required" desc:"Repository for the software artifacts (e.g., AWS CodeArtifact)"`
 
 	// Repository for the OCI (e.g., Docker) container images
 	ContainerRepo struct {
-		Provider string `yaml:"provider"`
-		Region string `yaml:"region"`
-		AccountId string `yaml:"acct_id"`
-		Domain string `yaml:"domain"`
-		Name string `yaml:"name"`
-	} `yaml:"container_repo"`
+		CloudLocation `yaml:",inline"`
+		Domain string `yaml:"domain" desc:"Domain of the container repository"`
+		Name string `yaml:"name" validate:"required" desc:"Name of the container repository"`
+	} `yaml:"container_repo" validate:"required" desc:"Repository for the OCI container images (e.g., AWS ECR)"`
 
 	// Airflow service (e.g., AWS MWAA)
 	Airflow struct {
-		Provider string `yaml:"provider"`
-		Region string `yaml:"region"`
-		AccountId string `yaml:"acct_id"`
-		Domain string `yaml:"domain"`
+		CloudLocation `yaml:",inline"`
+		Domain string `yaml:"domain" validate:"required" desc:"Name of the Airflow environment"`
 
 		//
 		Dag struct {
-			NamePattern string `yaml:"name_pattern"`
-			Tag string `yaml:"tag"`
-		} `yaml:"dag"`
+			NamePattern string `yaml:"name_pattern" validate:"required,regex" desc:"Regular expression matching the names of the deployed DAGs"`
+			Tag string `yaml:"tag" desc:"Tag of the DAGs"`
+			// Local directory holding the DAG files to be deployed
+			SourceDir string `yaml:"source_dir" desc:"Local directory holding the DAG files to be deployed"`
+		} `yaml:"dag" validate:"required" desc:"Airflow DAGs of the pipeline"`
 
 		StorageContainer struct {
-			Name string `yaml:"name"`
-			Prefix string `yaml:"prefix"`
-		} `yaml:"storage_container"`
-	} `yaml:"airflow"`
+			Name string `yaml:"name" validate:"required,bucket" desc:"Name of the bucket of the DAG folder"`
+			Prefix string `yaml:"prefix" desc:"Prefix of the DAG folder within the bucket"`
+		} `yaml:"storage_container" validate:"required" desc:"Storage container of the DAG folder of Airflow"`
+	} `yaml:"airflow" validate:"required" desc:"Airflow service (e.g., AWS MWAA)"`
 
 	// Compute engine (e.g., Spark on DataBricks, Spark on AWS EMR)
 	ComputeEngine struct {
-		Provider string `yaml:"provider"`
-		Region string `yaml:"region"`
-		AccountId string `yaml:"acct_id"`
-		Domain string `yaml:"domain"`
+		CloudLocation `yaml:",inline"`
+		Domain string `yaml:"domain" desc:"Domain of the compute engine"`
+		Engine string `yaml:"engine,omitempty" desc:"Kind of compute engine, against which the versions of Spark and Delta Lake are checked (by default, emr for the aws provider)" enum:"emr,databricks"`
 
 		//
 		Cluster struct {
-			Name string `yaml:"name"`
-			Version string `yaml:"version"`
-		} `yaml:"cluster"`
-	} `yaml:"compute_engine"`
+			Name string `yaml:"name" validate:"required" desc:"Name of the cluster"`
+			Version string `yaml:"version" desc:"Release of the cluster, checked against the kind of compute engine: EMR release (e.g., emr-6.9.0 or 6.9.0) or Databricks runtime (e.g., 13.3.x-scala2.12)"`
+			// Override of the compatibility table, for the releases it
+			// does not know about yet
+			SparkVersion string `yaml:"spark_version,omitempty" validate:"semver" desc:"Version of Spark of the release (e.g., 3.3.0), required when the release is not in the compatibility table of dppctl, which it overrides otherwise"`
+		} `yaml:"cluster" validate:"required" desc:"Cluster of the compute engine"`
+	} `yaml:"compute_engine" desc:"Compute engine (e.g., Spark on AWS EMR, Spark on DataBricks)"`
 
 
 	// Kubernetes service (e.g., AWS EKS)
 	Kubernetes struct {
-		Provider string `yaml:"provider"`
-		Region string `yaml:"region"`
-		AccountId string `yaml:"acct_id"`
-		Domain string `yaml:"domain"`
-		Namespace string `yaml:"Namespace"`
-	} `yaml:"kubernetes"`
+		CloudLocation `yaml:",inline"`
+		Domain string `yaml:"domain" desc:"Domain of the Kubernetes service"`
+		Namespace string `yaml:"namespace" desc:"Kubernetes namespace"`
+	} `yaml:"kubernetes" desc:"Kubernetes service (e.g., AWS EKS)"`
 }
 
-func ReadSpecFile(specFilepath string) (SpecFile, error) {
+// Read a specification, made of one or several layered files (see
+// SpecLayers for the merge rules)
+func ReadSpecFile(specFilepaths ...string) (SpecFile, error) {
 	t := SpecFile{}
 
-    yamlFile, err := ioutil.ReadFile(specFilepath)
-    if err != nil {
-        log.Fatalf("Error while reading the specification file: %v", err)
-    }
-	
-	err = yaml.Unmarshal(yamlFile, &t)
+	layers, err := LoadSpecLayers(specFilepaths...)
 	if err != nil {
-		log.Fatalf("error: %v", err)
+		return t, err
+	}
+
+	// The fields of the former format would otherwise be silently
+	// ignored, leaving nothing to be checked nor deployed
+	err = legacySpecError(layers)
+	if err != nil {
+		return t, fmt.Errorf("the %s specification has the former format: %w",
+			strings.Join(layers.Files, " + "), err)
+	}
+
+	err = layers.Root.Decode(&t)
+	if err != nil {
+		return t, fmt.Errorf("the %s specification cannot be decoded: %w",
+			strings.Join(layers.Files, " + "), err)
 	}
     
     return t, nil
 }
 
+// Fields of the former format of the specification, which are refused,
+// with the way to migrate them
+var legacySpecFields = []struct {
+	Path    []string
+	Kind    yaml.Kind
+	Message string
+}{
+	{[]string{"container", "module"}, 0,
+		"the module is now an element of the container.modules list (e.g., `modules: [{stack: python, name: example-module, version: 0.0.1}]`)"},
+	{[]string{"container", "dependencies"}, yaml.MappingNode,
+		"the dependencies (e.g., spark and delta_spark) are now elements of the container.dependencies list (e.g., `dependencies: [{name: pyspark, version: 3.3.0}, {name: delta-spark, version: 2.1.1}]`)"},
+}
+
+// Errors for the fields of the former format of the specification, if any,
+// giving the file, line and column of each of them
+func legacySpecError(layers *SpecLayers) error {
+	legacyErrors := []error{}
+	for _, field := range legacySpecFields {
+		node := specNode(layers.Root, field.Path...)
+		if node == nil || (field.Kind != 0 && node.Kind != field.Kind) {
+			continue
+		}
+		legacyErrors = append(legacyErrors, ValidationError{
+			File: layers.Sources[node], Line: node.Line, Column: node.Column,
+			Path: strings.Join(field.Path, "."), Message: field.Message})
+	}
+	return errors.Join(legacyErrors...)
+}
+
+// Format of a package (of a module or a dependency) within the artifact
+// repository, given the format specified for it, if any
+func (deplSpec SpecFile) PackageFormat(format string) string {
+	if format != "" {
+		return format
+	}
+	return deplSpec.ArtifactRepo.Format
+}
@@ -0,0 +1,47 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/depl_test.go
+//
+package utilities
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+/**
+ * Check that a specification of the former format (a single module,
+ * and the spark and delta_spark dependencies) is refused, instead of
+ * giving no module nor dependency to be checked
+ */
+func TestReadLegacySpecFile(t *testing.T) {
+	specFilepath := filepath.Join(t.TempDir(), "spec.yaml")
+	err := os.WriteFile(specFilepath, []byte(`metadata:
+  env: dev
+  project: example-project
+container:
+  module:
+    stack: python
+    name: induction-spark-basic
+    version: 0.0.1
+  dependencies:
+    spark:
+      version: 3.3.0
+    delta_spark:
+      version: 2.1.1
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ReadSpecFile(specFilepath)
+	for _, expected := range []string{
+		specFilepath + ":6:5: container.module: the module is now an element of the container.modules list",
+		specFilepath + ":10:5: container.dependencies: the dependencies (e.g., spark and delta_spark) are now elements of the container.dependencies list",
+	} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf(`ReadSpecFile() = %v, expected %q`, err, expected)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/interpolate.go
+//
+package utilities
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// `$$` (escaped dollar sign) or `${expression}`
+var interpolationRegex = regexp.MustCompile(`\$\$|\$\{([^}]*)\}`)
+
+var (
+	envVarNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	specPathRegex   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z0-9_]+)+$`)
+)
+
+/**
+ * Interpolation of the scalar values of the (merged) specification:
+ *   + `${VAR}` is replaced by the value of the VAR environment variable
+ *   + `${VAR:-default}` is replaced by the default value when the VAR
+ *     environment variable is not set or is empty
+ *   + `${section.field}` (i.e., a name with dots) is replaced by the value
+ *     of another field of the specification (e.g., `${metadata.project}`),
+ *     itself interpolated. The items of the lists are referred to by their
+ *     index (e.g., `${container.modules.0.name}`)
+ *   + `$$` is replaced by a single `$`
+ * Any reference which cannot be resolved is an error
+ */
+type interpolator struct {
+	layers    *SpecLayers
+	lookupEnv func(string) (string, bool)
+	// Nodes already interpolated, and being interpolated (to detect
+	// the references to each other)
+	resolved  map[*yaml.Node]bool
+	resolving map[*yaml.Node]bool
+	errors    []error
+}
+
+func (layers *SpecLayers) interpolate(lookupEnv func(string) (string,
+	bool)) error {
+	interpolator := &interpolator{layers: layers, lookupEnv: lookupEnv,
+		resolved: map[*yaml.Node]bool{}, resolving: map[*yaml.Node]bool{}}
+	interpolator.walk(layers.Root)
+	return errors.Join(interpolator.errors...)
+}
+
+func (i *interpolator) walk(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		// Only the values are interpolated, not the keys
+		for idx := 1; idx < len(node.Content); idx += 2 {
+			i.walk(node.Content[idx])
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			i.walk(item)
+		}
+	case yaml.ScalarNode:
+		i.resolve(node)
+	}
+}
+
+func (i *interpolator) addError(node *yaml.Node, format string,
+	args ...interface{}) {
+	i.errors = append(i.errors, fmt.Errorf("%s:%d:%d: %s",
+		i.layers.Sources[node], node.Line, node.Column,
+		fmt.Sprintf(format, args...)))
+}
+
+func (i *interpolator) resolve(node *yaml.Node) {
+	if i.resolved[node] || !strings.Contains(node.Value, "$") {
+		return
+	}
+	if i.resolving[node] {
+		i.addError(node, "the %q value refers to itself", node.Value)
+		return
+	}
+	i.resolving[node] = true
+	defer delete(i.resolving, node)
+
+	interpolated := interpolationRegex.ReplaceAllStringFunc(node.Value,
+		func(match string) string {
+			if match == "$$" {
+				return "$"
+			}
+			expression := match[2 : len(match)-1]
+			name, defaultValue, hasDefault := strings.Cut(expression, ":-")
+
+			var value string
+			var found bool
+			switch {
+			case specPathRegex.MatchString(name):
+				value, found = i.lookupSpec(node, name)
+			case envVarNameRegex.MatchString(name):
+				value, found = i.lookupEnv(name)
+				found = found && (value != "" || !hasDefault)
+			default:
+				i.addError(node, "%q is neither an environment variable nor a reference to a field of the specification",
+					match)
+				return match
+			}
+
+			if found {
+				return value
+			}
+			if hasDefault {
+				return defaultValue
+			}
+			i.addError(node, "%s cannot be resolved", match)
+			return match
+		})
+
+	if interpolated != node.Value {
+		node.Value = interpolated
+		node.Tag = "!!str"
+	}
+	i.resolved[node] = true
+}
+
+// Value of another field of the specification, given its path
+func (i *interpolator) lookupSpec(from *yaml.Node, path string) (string,
+	bool) {
+	node := i.layers.Root
+	for _, segment := range strings.Split(path, ".") {
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for idx := 0; idx+1 < len(node.Content); idx += 2 {
+				if node.Content[idx].Value == segment {
+					next = node.Content[idx+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			idx, err := strconv.Atoi(segment)
+			if err == nil && idx >= 0 && idx < len(node.Content) {
+				next = node.Content[idx]
+			}
+		}
+		if next == nil {
+			return "", false
+		}
+		node = next
+	}
+
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.ScalarNode || isNullNode(node) {
+		i.addError(from, "${%s} does not refer to a scalar value", path)
+		return "", true
+	}
+
+	i.resolve(node)
+	return node.Value, true
+}
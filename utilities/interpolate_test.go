@@ -0,0 +1,59 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/interpolate_test.go
+//
+package utilities
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+/**
+ * Check that the environment variables and the references to other fields
+ * are interpolated, and that the unresolved references are errors
+ */
+func TestSpecInterpolation(t *testing.T) {
+	t.Setenv("DPPCTL_TEST_ENV", "staging")
+	t.Setenv("DPPCTL_TEST_EMPTY", "")
+
+	specFilepath := filepath.Join(t.TempDir(), "spec.yaml")
+	err := os.WriteFile(specFilepath, []byte(`metadata:
+  env: ${DPPCTL_TEST_ENV}
+  project: ${DPPCTL_TEST_EMPTY:-example-project}
+  git_url: https://example.com/$${HOME}
+storage_container:
+  name: ${metadata.project}-${metadata.env}
+  prefix: ${storage_container.name}/dags
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deplSpec, err := ReadSpecFile(specFilepath)
+	if err != nil {
+		t.Fatalf(`ReadSpecFile() = %v`, err)
+	}
+	if deplSpec.StorageContainer.Name != "example-project-staging" ||
+		deplSpec.StorageContainer.Prefix != "example-project-staging/dags" ||
+		deplSpec.Metadata.GitUrl != "https://example.com/${HOME}" {
+		t.Errorf(`ReadSpecFile() = %+v, %+v, unexpected interpolation`,
+			deplSpec.Metadata, deplSpec.StorageContainer)
+	}
+
+	err = os.WriteFile(specFilepath, []byte(`metadata:
+  env: ${DPPCTL_TEST_UNSET}
+  project: ${metadata.projet}
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ReadSpecFile(specFilepath)
+	for _, expected := range []string{"2:8: ${DPPCTL_TEST_UNSET} cannot be resolved",
+		"3:12: ${metadata.projet} cannot be resolved"} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf(`ReadSpecFile() = %v, expected %q`, err, expected)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/layers.go
+//
+package utilities
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Key of a specification file giving the base file onto which it is overlaid
+const extendsKey = "extends"
+
+/**
+ * Deployment specification made of layered files, e.g., a base file
+ * shared by all the environments and an overlay per environment.
+ * The layers are merged, in order, into a single YAML tree:
+ *   + mappings are merged key by key, recursively
+ *   + sequences (lists) are replaced as a whole by the overlay
+ *   + scalars are replaced by the overlay
+ *   + a null value (e.g., `key: ~`) in an overlay clears the value
+ *     of the base (a null mapping or sequence being replaced as well)
+ * The sections then inherit the values of the `defaults` block (see
+ * applyDefaults()), and the values are eventually interpolated
+ * (see interpolator).
+ * A file may extend a base file (`extends: base.yaml`, relative to
+ * the file); the base file is merged first, and may itself extend
+ * another file
+ */
+type SpecLayers struct {
+	// Merged YAML tree (a mapping node)
+	Root *yaml.Node
+	// Source file of every node of the merged tree
+	Sources map[*yaml.Node]string
+	// Files, in the order in which they have been merged
+	Files []string
+	// Nodes inherited from the `defaults` block
+	Inherited map[*yaml.Node]bool
+}
+
+func LoadSpecLayers(specFilepaths ...string) (*SpecLayers, error) {
+	layers := &SpecLayers{Sources: map[*yaml.Node]string{},
+		Inherited: map[*yaml.Node]bool{}}
+	if len(specFilepaths) == 0 {
+		return layers, errors.New("no specification file")
+	}
+
+	for _, specFilepath := range specFilepaths {
+		root, err := layers.loadFile(specFilepath, []string{})
+		if err != nil {
+			return layers, err
+		}
+		layers.Root = layers.merge(layers.Root, root)
+	}
+	if layers.Root == nil {
+		layers.Root = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+
+	layers.applyDefaults()
+
+	// The references are resolved on the merged specification, so that
+	// an overlay changing a field changes the values referring to it
+	err := layers.interpolate(os.LookupEnv)
+	return layers, err
+}
+
+// Load a file, merged onto the file it extends, if any. The chain
+// of the files being loaded is kept, in order to detect the cycles
+func (layers *SpecLayers) loadFile(specFilepath string,
+	chain []string) (*yaml.Node, error) {
+	for _, chainedFilepath := range chain {
+		if filepath.Clean(chainedFilepath) == filepath.Clean(specFilepath) {
+			return nil, fmt.Errorf("the specification files extend each other: %s",
+				strings.Join(append(chain, specFilepath), " -> "))
+		}
+	}
+	chain = append(chain, specFilepath)
+
+	content, err := os.ReadFile(specFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("the specification file cannot be read: %w",
+			err)
+	}
+
+	var document yaml.Node
+	err = yaml.Unmarshal(content, &document)
+	if err != nil {
+		return nil, fmt.Errorf("the %s specification file cannot be parsed: %w",
+			specFilepath, err)
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		layers.Files = append(layers.Files, specFilepath)
+		return nil, nil
+	}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s:%d:%d: the specification is expected to be a mapping",
+			specFilepath, root.Line, root.Column)
+	}
+	layers.recordSources(root, specFilepath)
+
+	// Base file, which the file extends
+	var base *yaml.Node
+	for idx := 0; idx+1 < len(root.Content); idx += 2 {
+		if root.Content[idx].Value != extendsKey {
+			continue
+		}
+		extendsNode := root.Content[idx+1]
+		if extendsNode.Kind != yaml.ScalarNode || extendsNode.Value == "" {
+			return nil, fmt.Errorf("%s:%d:%d: %s: the path of a specification file is expected",
+				specFilepath, extendsNode.Line, extendsNode.Column, extendsKey)
+		}
+		baseFilepath := extendsNode.Value
+		if !filepath.IsAbs(baseFilepath) {
+			baseFilepath = filepath.Join(filepath.Dir(specFilepath),
+				baseFilepath)
+		}
+		base, err = layers.loadFile(baseFilepath, chain)
+		if err != nil {
+			return nil, err
+		}
+		root.Content = append(root.Content[:idx], root.Content[idx+2:]...)
+		break
+	}
+
+	layers.Files = append(layers.Files, specFilepath)
+	return layers.merge(base, root), nil
+}
+
+func (layers *SpecLayers) recordSources(node *yaml.Node, specFilepath string) {
+	layers.Sources[node] = specFilepath
+	for _, child := range node.Content {
+		layers.recordSources(child, specFilepath)
+	}
+}
+
+// Merge an overlay onto a base node, following the rules documented
+// on SpecLayers. The nodes themselves are left untouched
+func (layers *SpecLayers) merge(base *yaml.Node, overlay *yaml.Node) *yaml.Node {
+	if base == nil {
+		return overlay
+	}
+	if overlay == nil {
+		return base
+	}
+	if base.Kind != yaml.MappingNode || overlay.Kind != yaml.MappingNode {
+		return overlay
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: base.Tag,
+		Line: overlay.Line, Column: overlay.Column}
+	merged.Content = append(merged.Content, base.Content...)
+	layers.Sources[merged] = layers.Sources[overlay]
+
+	for idx := 0; idx+1 < len(overlay.Content); idx += 2 {
+		keyNode, valueNode := overlay.Content[idx], overlay.Content[idx+1]
+
+		mergedIdx := -1
+		for candidateIdx := 0; candidateIdx+1 < len(merged.Content); candidateIdx += 2 {
+			if merged.Content[candidateIdx].Value == keyNode.Value {
+				mergedIdx = candidateIdx
+				break
+			}
+		}
+
+		if mergedIdx >= 0 {
+			merged.Content[mergedIdx+1] = layers.merge(merged.Content[mergedIdx+1],
+				valueNode)
+		} else {
+			merged.Content = append(merged.Content, keyNode, valueNode)
+		}
+	}
+
+	return merged
+}
+
+/**
+ * Effective specification, as YAML, where every value is commented with
+ * the file it comes from
+ */
+func (layers *SpecLayers) Render() ([]byte, error) {
+	annotated := layers.annotate(layers.Root)
+	var rendered strings.Builder
+	encoder := yaml.NewEncoder(&rendered)
+	encoder.SetIndent(2)
+	err := encoder.Encode(annotated)
+	if err != nil {
+		return nil, err
+	}
+	err = encoder.Close()
+	return []byte(rendered.String()), err
+}
+
+// Copy of the tree, with the source files as line comments of the scalar
+// values (the mappings may be merged from several files)
+func (layers *SpecLayers) annotate(node *yaml.Node) *yaml.Node {
+	annotated := *node
+	annotated.HeadComment = ""
+	annotated.LineComment = ""
+	annotated.FootComment = ""
+	annotated.Content = nil
+
+	for idx, child := range node.Content {
+		annotatedChild := layers.annotate(child)
+		if node.Kind == yaml.MappingNode && idx%2 == 0 {
+			// Key of a mapping
+			annotatedChild.LineComment = ""
+		}
+		annotated.Content = append(annotated.Content, annotatedChild)
+	}
+	if node.Kind == yaml.ScalarNode || node.Kind == yaml.AliasNode {
+		annotated.LineComment = layers.Sources[node]
+		if layers.Inherited[node] {
+			annotated.LineComment += " (defaults)"
+		}
+	}
+
+	return &annotated
+}
+
+// Key of the block of the values inherited by the sections
+const defaultsKey = "defaults"
+
+/**
+ * Every section of the specification having inheritable fields
+ * (see CloudLocation) gets, for each of those fields it does not
+ * specify, a copy of the value of the `defaults` block, if not null
+ * nor empty. The sections absent from the specification are left absent
+ */
+func (layers *SpecLayers) applyDefaults() {
+	defaults := mappingValue(layers.Root, defaultsKey)
+	if defaults == nil || defaults.Kind != yaml.MappingNode {
+		return
+	}
+
+	for _, section := range specFields(reflect.TypeOf(SpecFile{})) {
+		if section.Type.Kind() != reflect.Struct {
+			continue
+		}
+		sectionNode := mappingValue(layers.Root, section.Name)
+		if sectionNode == nil || sectionNode.Kind != yaml.MappingNode {
+			continue
+		}
+
+		for _, field := range specFields(section.Type) {
+			if !field.Inheritable || mappingValue(sectionNode, field.Name) != nil {
+				continue
+			}
+			defaultNode := mappingValue(defaults, field.Name)
+			if defaultNode == nil || isNullNode(defaultNode) ||
+				(defaultNode.Kind == yaml.ScalarNode && defaultNode.Value == "") {
+				continue
+			}
+			keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str",
+				Value: field.Name, Line: defaultNode.Line,
+				Column: defaultNode.Column}
+			valueNode := layers.copyNode(defaultNode)
+			layers.Sources[keyNode] = layers.Sources[defaultNode]
+			sectionNode.Content = append(sectionNode.Content, keyNode,
+				valueNode)
+		}
+	}
+}
+
+// Value of a key of a mapping node, if any
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		if node.Content[idx].Value == key {
+			return node.Content[idx+1]
+		}
+	}
+	return nil
+}
+
+// Deep copy of an inherited node, so that it is interpolated on its own
+func (layers *SpecLayers) copyNode(node *yaml.Node) *yaml.Node {
+	copied := *node
+	copied.Content = nil
+	for _, child := range node.Content {
+		copied.Content = append(copied.Content, layers.copyNode(child))
+	}
+	layers.Sources[&copied] = layers.Sources[node]
+	layers.Inherited[&copied] = true
+	return &copied
+}
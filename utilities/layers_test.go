@@ -0,0 +1,80 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/layers_test.go
+//
+package utilities
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+/**
+ * Check that the layered specification files are merged (mappings merged
+ * key by key, lists replaced) and that the source of every value is known
+ */
+func TestSpecLayers(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name string, content string) string {
+		specFilepath := filepath.Join(dir, name)
+		err := os.WriteFile(specFilepath, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return specFilepath
+	}
+	basePath := writeFile("base.yaml", `defaults:
+  provider: aws
+  region: eu-west-1
+metadata:
+  env: dev
+  project: example-project
+storage_container:
+  name: example-bucket
+  prefix: example-prefix
+`)
+	prodPath := writeFile("prod.yaml", `extends: base.yaml
+metadata:
+  env: prod
+storage_container:
+  region: eu-west-3
+  prefix: ~
+`)
+
+	deplSpec, err := ReadSpecFile(prodPath)
+	if err != nil {
+		t.Fatalf(`ReadSpecFile() = %v`, err)
+	}
+	if deplSpec.Metadata.Env != "prod" || deplSpec.Metadata.Project != "example-project" ||
+		deplSpec.StorageContainer.Name != "example-bucket" ||
+		deplSpec.StorageContainer.Prefix != "" ||
+		deplSpec.StorageContainer.Provider != "aws" ||
+		deplSpec.StorageContainer.Region != "eu-west-3" {
+		t.Errorf(`ReadSpecFile() = %+v, %+v, unexpected merge`,
+			deplSpec.Metadata, deplSpec.StorageContainer)
+	}
+
+	layers, err := LoadSpecLayers(prodPath)
+	if err != nil {
+		t.Fatalf(`LoadSpecLayers() = %v`, err)
+	}
+	rendered, err := layers.Render()
+	if err != nil {
+		t.Fatalf(`layers.Render() = %v`, err)
+	}
+	for _, expected := range []string{"env: prod # " + prodPath,
+		"project: example-project # " + basePath,
+		"provider: aws # " + basePath + " (defaults)"} {
+		if !strings.Contains(string(rendered), expected) {
+			t.Errorf(`layers.Render() = %s, expected %q`, rendered, expected)
+		}
+	}
+
+	// The files extending each other are refused
+	writeFile("base.yaml", "extends: prod.yaml\n")
+	_, err = LoadSpecLayers(prodPath)
+	if err == nil || !strings.Contains(err.Error(), "extend each other") {
+		t.Errorf(`LoadSpecLayers() = %v, expected a cycle error`, err)
+	}
+}
@@ -11,10 +11,10 @@ import (
 )
 
 type MwaaDagMetadata struct {
-	DagId string `json:"dag_id"`
-	Filepath string `json:"filepath"`
-	Owner string `json:"owner"`
-	Paused string `json:"paused"`
+	DagId string `json:"dag_id" yaml:"dag_id"`
+	Filepath string `json:"filepath" yaml:"filepath"`
+	Owner string `json:"owner" yaml:"owner"`
+	Paused string `json:"paused" yaml:"paused"`
 }
 
 func ParseAWSMWAADagListOutput(rawOutput string) ([]MwaaDagMetadata, error) {
@@ -44,7 +44,11 @@ func ExtractMatchingAWSMWAADagList(mwaaDagMetadataList []MwaaDagMetadata,
 
 	// Build a RegExp from the given name pattern
 	nameRegex := fmt.Sprintf(".*%s.*", namePattern)
-	re := regexp.MustCompile(nameRegex)
+	re, err := regexp.Compile(nameRegex)
+	if err != nil {
+		return dagList, fmt.Errorf("the %s DAG name pattern is not a valid regular expression: %w",
+			namePattern, err)
+	}
 	
 	for _, dag := range mwaaDagMetadataList {
 		dagId := dag.DagId
@@ -0,0 +1,77 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/requirement.go
+//
+package utilities
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var requirementClauseRegex = regexp.MustCompile(`^(===|~=|==|!=|>=|<=|>|<)\s*(\S+)$`)
+
+/**
+ * Constraint on the versions of a requirement declared by a Python package
+ * (PEP 440 version specifier, e.g., `==3.3.0`, `>=3.3,<3.4`, `~=3.3.0`
+ * or `==3.3.*`), so that it may be matched against a semantic version:
+ *   + `==3.3.*`: the versions having that prefix (`>=3.3.0,<3.4.0`)
+ *   + `~=3.3.0`: the compatible versions (`>=3.3.0,<3.4.0`), and `~=3.3`:
+ *     `>=3.3.0,<4.0.0`
+ *   + `==`, `===`, `!=`, `>=`, `<=`, `>`, `<`: the comparisons
+ * The specifier may be enclosed in parentheses (e.g., `(==3.3.0)`), and
+ * is empty when any version is accepted. The versions which are not
+ * made of up to three numbers (e.g., `3.3.0.post1`) are not supported
+ * Reference: https://packaging.python.org/en/latest/specifications/version-specifiers/
+ */
+func ParseRequirementSpecifier(specifier string) (VersionConstraint, error) {
+	versionConstraint := VersionConstraint{Constraint: specifier}
+	trimmed := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(
+		strings.TrimSpace(specifier), "("), ")"))
+	if trimmed == "" {
+		return versionConstraint, nil
+	}
+
+	for _, clause := range strings.Split(trimmed, ",") {
+		match := requirementClauseRegex.FindStringSubmatch(strings.TrimSpace(clause))
+		if match == nil {
+			return versionConstraint, fmt.Errorf("%q is not a version specifier (e.g., ==3.3.0, >=3.3,<3.4 or ~=3.3.0)",
+				specifier)
+		}
+		operator, version := match[1], match[2]
+		wildcard := strings.HasSuffix(version, ".*")
+		partial := partialVersionRegex.FindStringSubmatch(
+			strings.TrimSuffix(version, ".*"))
+		if partial == nil || (wildcard && operator != "==") {
+			return versionConstraint, fmt.Errorf("the %q clause of the %q version specifier is not supported",
+				clause, specifier)
+		}
+
+		comparisons := []versionComparison{}
+		switch {
+		case wildcard:
+			comparisons = expandComparison("~", partial)
+		case operator == "~=":
+			if partial[2] == "" {
+				return versionConstraint, fmt.Errorf("the %q clause of the %q version specifier needs at least two numbers",
+					clause, specifier)
+			}
+			comparisons = expandComparison("~", partial)
+			if partial[3] == "" {
+				// ~=3.3 allows any later 3.x version
+				major, _ := strconv.Atoi(partial[1])
+				comparisons[1].version = SemVer{Major: major + 1,
+					Prerelease: "0"}
+			}
+		case operator == "==" || operator == "===":
+			comparisons = expandComparison("=", partial)
+		default:
+			comparisons = expandComparison(operator, partial)
+		}
+		versionConstraint.comparisons = append(versionConstraint.comparisons,
+			comparisons...)
+	}
+
+	return versionConstraint, nil
+}
@@ -0,0 +1,48 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/requirement_test.go
+//
+package utilities
+
+import (
+	"testing"
+)
+
+/**
+ * Check that the version specifiers of the requirements (PEP 440)
+ * are parsed and matched against the versions
+ */
+func TestRequirementSpecifier(t *testing.T) {
+	specifiers := []struct {
+		specifier string
+		version   string
+		expected  bool
+	}{
+		{"(==3.3.0)", "3.3.0", true},
+		{"==3.3", "3.3.0", true},
+		{"==3.3.*", "3.3.2", true},
+		{"==3.3.*", "3.4.0", false},
+		{"~=3.3.0", "3.3.5", true},
+		{"~=3.3.0", "3.4.0", false},
+		{"~=3.3", "3.5.1", true},
+		{">=3.3, <3.4", "3.4.0", false},
+		{"!=3.3.1", "3.3.1", false},
+		{"", "3.3.0", true},
+	}
+	for _, test := range specifiers {
+		constraint, err := ParseRequirementSpecifier(test.specifier)
+		if err != nil {
+			t.Errorf(`ParseRequirementSpecifier(%q) = %v`,
+				test.specifier, err)
+			continue
+		}
+		version, _ := ParseSemVer(test.version)
+		if constraint.Matches(version) != test.expected {
+			t.Errorf(`%q matches %s = %t, expected %t`, test.specifier,
+				test.version, !test.expected, test.expected)
+		}
+	}
+	_, err := ParseRequirementSpecifier(">=3.3.0.post1")
+	if err == nil {
+		t.Errorf(`ParseRequirementSpecifier(">=3.3.0.post1") = nil, expected an error`)
+	}
+}
@@ -0,0 +1,149 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/schema.go
+//
+package utilities
+
+import (
+	"reflect"
+)
+
+// Location of the published JSON Schema, referred to by the `$id` keyword
+const SpecSchemaId = "https://raw.githubusercontent.com/data-engineering-helpers/dppctl/main/schema/dppctl-spec.schema.json"
+
+// Subset of the JSON Schema (draft 07) keywords, which is enough
+// to describe the deployment specification
+// Reference: https://json-schema.org/draft-07/json-schema-release-notes.html
+type JSONSchema struct {
+	Schema      string `json:"$schema,omitempty" yaml:"$schema,omitempty"`
+	Id          string `json:"$id,omitempty" yaml:"$id,omitempty"`
+	Title       string `json:"title,omitempty" yaml:"title,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Either a single type or a list of types
+	Type       interface{}            `json:"type,omitempty" yaml:"type,omitempty"`
+	Properties map[string]*JSONSchema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required   []string               `json:"required,omitempty" yaml:"required,omitempty"`
+	// Either false or the schema of the additional properties
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Items                *JSONSchema `json:"items,omitempty" yaml:"items,omitempty"`
+	Enum                 []string    `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Pattern              string      `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Format               string      `json:"format,omitempty" yaml:"format,omitempty"`
+	MinLength            int         `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MinItems             int         `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	Minimum              int64       `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum              int64       `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+}
+
+// Translation of the formats of the `validate` struct tags into
+// JSON Schema keywords. The checks which cannot be expressed with
+// a regular expression (e.g., the reserved prefixes of the bucket names)
+// are only performed by ValidateSpec()
+var formatSchemas = map[string]func(*JSONSchema){
+	"region": func(schema *JSONSchema) {
+		schema.Pattern = regionRegex.String()
+	},
+	// YAML reads the unquoted account IDs as integers, to which
+	// the pattern does not apply: they then have 12 digits, the account
+	// IDs beginning with a 0 having to be quoted
+	"account_id": func(schema *JSONSchema) {
+		schema.Type = []string{"string", "integer"}
+		schema.Pattern = accountIdRegex.String()
+		schema.Minimum = 100000000000
+		schema.Maximum = 999999999999
+	},
+	"semver": func(schema *JSONSchema) {
+		schema.Pattern = semVerRegex.String()
+	},
+	// The constraints are only roughly checked (see ParseVersionConstraint())
+	"constraint": func(schema *JSONSchema) {
+		schema.Pattern = versionConstraintPattern
+	},
+	"bucket": func(schema *JSONSchema) {
+		schema.Pattern = bucketRegex.String()
+	},
+	"regex": func(schema *JSONSchema) {
+		schema.Format = "regex"
+	},
+}
+
+// Version constraints, e.g., `~0.0.1`, `>=0.1,<0.2` or `latest`
+const versionConstraintPattern = `^(latest|([!=<>~^]*\s*[0-9][0-9A-Za-z.+-]*[\s,]*)+)$`
+
+/**
+ * JSON Schema of the deployment specification, derived from the struct
+ * tags of SpecFile: `yaml` (field names), `validate` (required fields
+ * and formats), `enum` (known values) and `desc` (descriptions).
+ * YAML language servers (e.g., in VS Code) use it for the completion
+ * and the validation of the specification files
+ */
+func GenerateSpecSchema() *JSONSchema {
+	schema := typeSchema(reflect.TypeOf(SpecFile{}))
+	schema.Schema = "http://json-schema.org/draft-07/schema#"
+	schema.Id = SpecSchemaId
+	schema.Title = "dppctl deployment specification"
+	schema.Description = "Deployment of a data processing pipeline, with the cloud services (storage, artifact and container repositories, Airflow, compute engine, Kubernetes) it relies upon"
+	return schema
+}
+
+func typeSchema(schemaType reflect.Type) *JSONSchema {
+	if schemaType.Kind() == reflect.Pointer {
+		schemaType = schemaType.Elem()
+	}
+
+	switch schemaType.Kind() {
+	case reflect.Struct:
+		schema := &JSONSchema{Type: "object",
+			Properties: map[string]*JSONSchema{}, AdditionalProperties: false}
+		for _, field := range specFields(schemaType) {
+			schema.Properties[field.Name] = fieldSchema(field)
+			// The inheritable fields may be given by the `defaults` block
+			// instead, which a JSON Schema cannot express
+			if field.Required && !field.Inheritable {
+				schema.Required = append(schema.Required, field.Name)
+			}
+		}
+		return schema
+
+	case reflect.Slice:
+		return &JSONSchema{Type: "array", Items: typeSchema(schemaType.Elem())}
+
+	case reflect.Map:
+		return &JSONSchema{Type: "object",
+			AdditionalProperties: typeSchema(schemaType.Elem())}
+
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	}
+
+	return &JSONSchema{Type: "string"}
+}
+
+func fieldSchema(field specField) *JSONSchema {
+	schema := typeSchema(field.Type)
+	schema.Description = field.Description
+	schema.Enum = field.Enum
+	for _, format := range field.Formats {
+		formatSchemas[format](schema)
+	}
+	if field.Required && schema.Type == "string" {
+		schema.MinLength = 1
+	}
+	if field.Required && schema.Type == "array" {
+		schema.MinItems = 1
+	}
+
+	// The values to be interpolated (e.g., `${AWS_ACCOUNT_ID}`) are only
+	// checked once interpolated (see ValidateSpecFile())
+	if schema.Pattern != "" {
+		schema.Pattern = "(?:" + schema.Pattern + `)|\$\{`
+	}
+	return schema
+}
@@ -0,0 +1,220 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/schema_test.go
+//
+package utilities_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/data-engineering-helpers/dppctl/report"
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+	"gopkg.in/yaml.v3"
+)
+
+/**
+ * Check that the published JSON Schema is up to date with the SpecFile
+ * structure (it is regenerated with `dppctl -c schema`), and that it
+ * knows about every registered provider
+ */
+func TestSpecSchema(t *testing.T) {
+	schemaFilepath := "../schema/dppctl-spec.schema.json"
+	published, err := os.ReadFile(schemaFilepath)
+	if err != nil {
+		t.Fatalf(`os.ReadFile(%q) = %v`, schemaFilepath, err)
+	}
+
+	schema := utilities.GenerateSpecSchema()
+	var generated bytes.Buffer
+	err = report.Render(&generated, report.FormatJSON, schema)
+	if err != nil {
+		t.Fatalf(`report.Render() = %v`, err)
+	}
+	if !bytes.Equal(published, generated.Bytes()) {
+		t.Errorf(`%s is out of date, regenerate it with: go run . -c schema > %s`,
+			schemaFilepath, schemaFilepath)
+	}
+
+	providerEnum := schema.Properties["artifact_repo"].Properties["provider"].Enum
+	providerNames := service.ProviderNames()
+	sort.Strings(providerEnum)
+	if strings.Join(providerEnum, ",") != strings.Join(providerNames, ",") {
+		t.Errorf(`provider enum = %v, expected the registered providers %v`,
+			providerEnum, providerNames)
+	}
+}
+
+/**
+ * Check that the JSON Schema and ValidateSpecFile() agree, i.e., report
+ * errors on the same fields, for the sample specification and for
+ * invalid variants of it. The variants only break the rules which both
+ * express (e.g., not the inherited required fields, nor the
+ * compatibility of the versions)
+ */
+func TestSpecSchemaAgreement(t *testing.T) {
+	sample, err := os.ReadFile("../depl/aws-dev-sample.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubernetes := "kubernetes:\n  domain: example-domain\n  namespace: example-namespace"
+	tests := []struct {
+		old      string
+		new      string
+		expected string
+	}{
+		{"", "", ""},
+		{"  env: dev\n", "  env: dev\n  owner: example-owner\n", "metadata.owner"},
+		{"    name: example-cluster", `    name: ""`, "compute_engine.cluster.name"},
+		{"    - name: delta-spark\n", "    - version_of: delta-spark\n",
+			"container.dependencies[1].name,container.dependencies[1].version_of"},
+		{kubernetes, kubernetes + "\n  region: eu-westish", "kubernetes.region"},
+		{kubernetes, kubernetes + "\n  acct_id: 1234", "kubernetes.acct_id"},
+		{kubernetes, kubernetes + "\n  provider: gcp", "kubernetes.provider"},
+		{kubernetes, "kubernetes: example", "kubernetes"},
+		{"      version: 0.0.1", "      version: first", "container.modules[0].version"},
+		{"    tag: example-tag", "    tag: [example-tag]", "airflow.dag.tag"},
+		{"    name_pattern: ${metadata.project}", `    name_pattern: "example-("`,
+			"airflow.dag.name_pattern"},
+		{"    name: ${storage_container.name}", "    name: Example_Bucket",
+			"airflow.storage_container.name"},
+	}
+	schema := utilities.GenerateSpecSchema()
+	for _, test := range tests {
+		spec := strings.Replace(string(sample), test.old, test.new, 1)
+
+		// Fields reported by the JSON Schema, on the raw specification
+		var document interface{}
+		err = yaml.Unmarshal([]byte(spec), &document)
+		if err != nil {
+			t.Fatal(err)
+		}
+		schemaPaths := map[string]bool{}
+		evaluateSchema(schema, document, "", schemaPaths)
+
+		// Fields reported by the validation
+		specFilepath := filepath.Join(t.TempDir(), "spec.yaml")
+		err = os.WriteFile(specFilepath, []byte(spec), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		validationErrors, err := utilities.ValidateSpecFile(specFilepath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		validationPaths := map[string]bool{}
+		for _, validationError := range validationErrors {
+			validationPaths[validationError.Path] = true
+		}
+
+		if sortedPaths(schemaPaths) != test.expected ||
+			sortedPaths(validationPaths) != test.expected {
+			t.Errorf(`%q: the JSON Schema reports %q and ValidateSpecFile() %v, expected %q`,
+				test.new, sortedPaths(schemaPaths), validationErrors, test.expected)
+		}
+	}
+}
+
+func sortedPaths(paths map[string]bool) string {
+	sorted := []string{}
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// Minimal evaluation of the JSON Schema keywords GenerateSpecSchema()
+// emits, collecting the paths of the values breaking them
+func evaluateSchema(schema *utilities.JSONSchema, value interface{},
+	path string, paths map[string]bool) {
+	joinPath := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+
+	types := []string{}
+	switch schemaType := schema.Type.(type) {
+	case string:
+		types = append(types, schemaType)
+	case []string:
+		types = append(types, schemaType...)
+	}
+	valueType := "null"
+	switch value.(type) {
+	case map[string]interface{}:
+		valueType = "object"
+	case []interface{}:
+		valueType = "array"
+	case string:
+		valueType = "string"
+	case int:
+		valueType = "integer"
+	case bool:
+		valueType = "boolean"
+	}
+	typeFound := false
+	for _, schemaType := range types {
+		typeFound = typeFound || schemaType == valueType
+	}
+	if !typeFound {
+		paths[path] = true
+		return
+	}
+
+	switch value := value.(type) {
+	case string:
+		if len(value) < schema.MinLength {
+			paths[path] = true
+		}
+		if schema.Pattern != "" && !regexp.MustCompile(schema.Pattern).MatchString(value) {
+			paths[path] = true
+		}
+		if len(schema.Enum) > 0 && !strings.Contains(","+strings.Join(schema.Enum, ",")+",",
+			","+value+",") {
+			paths[path] = true
+		}
+		if _, err := regexp.Compile(value); schema.Format == "regex" && err != nil {
+			paths[path] = true
+		}
+
+	case int:
+		if (schema.Minimum != 0 && int64(value) < schema.Minimum) ||
+			(schema.Maximum != 0 && int64(value) > schema.Maximum) {
+			paths[path] = true
+		}
+
+	case []interface{}:
+		if len(value) < schema.MinItems {
+			paths[path] = true
+		}
+		for idx, item := range value {
+			evaluateSchema(schema.Items, item, fmt.Sprintf("%s[%d]", path, idx),
+				paths)
+		}
+
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if _, found := value[name]; !found {
+				paths[joinPath(name)] = true
+			}
+		}
+		for name, item := range value {
+			if property, found := schema.Properties[name]; found {
+				evaluateSchema(property, item, joinPath(name), paths)
+			} else if additional, ok := schema.AdditionalProperties.(*utilities.JSONSchema); ok {
+				evaluateSchema(additional, item, joinPath(name), paths)
+			} else {
+				paths[joinPath(name)] = true
+			}
+		}
+	}
+}
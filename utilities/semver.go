@@ -0,0 +1,324 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/semver.go
+//
+package utilities
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Semantic version (see https://semver.org), e.g., 3.3.0 or 1.0.0-rc.1+build.5
+type SemVer struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	Build      string
+}
+
+// Regular expression suggested by https://semver.org
+var semVerRegex = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+func ParseSemVer(version string) (SemVer, error) {
+	semVer := SemVer{}
+
+	match := semVerRegex.FindStringSubmatch(version)
+	if match == nil {
+		return semVer, fmt.Errorf("%q is not a semantic version (e.g., 1.2.3)",
+			version)
+	}
+
+	// The numbers have been matched by the regular expression already
+	semVer.Major, _ = strconv.Atoi(match[1])
+	semVer.Minor, _ = strconv.Atoi(match[2])
+	semVer.Patch, _ = strconv.Atoi(match[3])
+	semVer.Prerelease = match[4]
+	semVer.Build = match[5]
+
+	return semVer, nil
+}
+
+func (v SemVer) String() string {
+	version := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		version += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		version += "+" + v.Build
+	}
+	return version
+}
+
+/**
+ * Compare two versions, following the precedence rules of semver.org:
+ * -1, 0 or +1 when the version is lower than, equal to or greater than
+ * the other one. A pre-release is lower than the associated normal
+ * version, and the build meta-data is ignored
+ */
+func (v SemVer) Compare(other SemVer) int {
+	for _, diff := range []int{v.Major - other.Major, v.Minor - other.Minor,
+		v.Patch - other.Patch} {
+		switch {
+		case diff < 0:
+			return -1
+		case diff > 0:
+			return 1
+		}
+	}
+
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+// The identifiers are compared one by one: numerically when both are
+// numbers, the numeric ones being lower than the alphanumeric ones
+func comparePrerelease(prerelease string, other string) int {
+	ids, otherIds := strings.Split(prerelease, "."), strings.Split(other, ".")
+	for idx := 0; idx < len(ids) && idx < len(otherIds); idx++ {
+		num, err := strconv.Atoi(ids[idx])
+		otherNum, otherErr := strconv.Atoi(otherIds[idx])
+		switch {
+		case err == nil && otherErr == nil && num != otherNum:
+			if num < otherNum {
+				return -1
+			}
+			return 1
+		case err == nil && otherErr != nil:
+			return -1
+		case err != nil && otherErr == nil:
+			return 1
+		case ids[idx] != otherIds[idx]:
+			return strings.Compare(ids[idx], otherIds[idx])
+		}
+	}
+	switch {
+	case len(ids) < len(otherIds):
+		return -1
+	case len(ids) > len(otherIds):
+		return 1
+	}
+	return 0
+}
+
+// Constraint matching any version, resolved to the highest one
+const LatestVersion = "latest"
+
+/**
+ * Constraint on the versions, made of comparisons which all have to be
+ * satisfied, separated by commas or spaces:
+ *   + `1.2.3`, `1.0`, `1.0-SNAPSHOT` or `1.2.3.post1`: a version without
+ *     operator is that exact version, taken literally (it does not have
+ *     to be a semantic one, e.g., for Maven or PyPI), and is not resolved
+ *   + `=1.2.3`: that exact version, the same as `1.2.3` (the operator
+ *     is dropped, see ExactVersion())
+ *   + `>=0.1`, `>0.1.2`, `<0.2`, `<=0.2.1`: comparisons, the missing
+ *     numbers being zeros (`>=0.1` being `>=0.1.0`)
+ *   + `!=0.1.3`: any version but that one (e.g., `~0.1,!=0.1.3`)
+ *   + `~1.2.3` or `~1.2`: the patch versions (`>=1.2.3,<1.3.0`),
+ *     and `~1`: the minor versions (`>=1.0.0,<2.0.0`)
+ *   + `^1.2.3`: the versions not changing the left-most non-zero
+ *     number (`>=1.2.3,<2.0.0`, but `^0.2.3` being `>=0.2.3,<0.3.0`)
+ *   + `latest`: any version
+ * The pre-releases (e.g., 1.2.3-rc.1) only match when one of the
+ * comparisons has a pre-release of the same major, minor and patch
+ * numbers, so that `latest` never selects a pre-release
+ */
+type VersionConstraint struct {
+	Constraint  string
+	comparisons []versionComparison
+	// Literal version, when the constraint is a version without operator
+	// (or with the sole `=` operator)
+	literal string
+}
+
+type versionComparison struct {
+	operator string
+	version  SemVer
+}
+
+// Version, the minor and patch numbers of which may be missing
+var partialVersionRegex = regexp.MustCompile(`^(0|[1-9]\d*)(?:\.(0|[1-9]\d*))?(?:\.(0|[1-9]\d*))?` +
+	`(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+
+// Literal version, e.g., 1.0-SNAPSHOT or 1.2.3.post1
+var literalVersionRegex = regexp.MustCompile(`^[0-9][0-9A-Za-z.+-]*$`)
+
+var (
+	constraintOperatorRegex = regexp.MustCompile(`^(>=|<=|!=|>|<|=|~|\^)?(.*)$`)
+	constraintSpacesRegex   = regexp.MustCompile(`(>=|<=|!=|>|<|=|~|\^)\s+`)
+)
+
+func ParseVersionConstraint(constraint string) (VersionConstraint,
+	error) {
+	versionConstraint := VersionConstraint{Constraint: constraint}
+	if strings.TrimSpace(constraint) == LatestVersion {
+		return versionConstraint, nil
+	}
+	// A single exact version (e.g., `=1.2.3`) is that version, taken
+	// literally as well
+	literal := strings.TrimSpace(strings.TrimPrefix(
+		strings.TrimSpace(constraint), "="))
+	if literalVersionRegex.MatchString(literal) {
+		versionConstraint.literal = literal
+		return versionConstraint, nil
+	}
+
+	// The spaces between an operator and its version are allowed
+	// (e.g., `>= 0.1, < 0.2`)
+	normalized := constraintSpacesRegex.ReplaceAllString(constraint, "$1")
+	terms := strings.FieldsFunc(normalized, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	if len(terms) == 0 {
+		return versionConstraint, fmt.Errorf("%q is not a version constraint (e.g., 1.2.3, ~1.2.3, >=0.1,<0.2 or latest)",
+			constraint)
+	}
+
+	for _, term := range terms {
+		match := constraintOperatorRegex.FindStringSubmatch(term)
+		operator := match[1]
+		version := partialVersionRegex.FindStringSubmatch(match[2])
+		if version == nil {
+			return versionConstraint, fmt.Errorf("%q is not a version constraint (e.g., 1.2.3, ~1.2.3, >=0.1,<0.2 or latest)",
+				constraint)
+		}
+		versionConstraint.comparisons = append(versionConstraint.comparisons,
+			expandComparison(operator, version)...)
+	}
+
+	return versionConstraint, nil
+}
+
+// Comparisons of a term of a constraint, given the operator and
+// the sub-matches of the (possibly partial) version
+func expandComparison(operator string,
+	match []string) []versionComparison {
+	lower := SemVer{Prerelease: match[4]}
+	lower.Major, _ = strconv.Atoi(match[1])
+	lower.Minor, _ = strconv.Atoi(match[2])
+	lower.Patch, _ = strconv.Atoi(match[3])
+	hasMinor, hasPatch := match[2] != "", match[3] != ""
+
+	// A version without operator, within a list, is an exact version
+	if operator == "" {
+		operator = "="
+	}
+
+	upper := SemVer{}
+	switch operator {
+	case "~":
+		upper = SemVer{Major: lower.Major, Minor: lower.Minor + 1}
+		if !hasMinor {
+			upper = SemVer{Major: lower.Major + 1}
+		}
+	case "^":
+		switch {
+		case lower.Major > 0 || !hasMinor:
+			upper = SemVer{Major: lower.Major + 1}
+		case lower.Minor > 0 || !hasPatch:
+			upper = SemVer{Minor: lower.Minor + 1}
+		default:
+			upper = SemVer{Patch: lower.Patch + 1}
+		}
+	default:
+		return []versionComparison{{operator, lower}}
+	}
+	// The upper bound excludes its pre-releases (e.g., 1.3.0-rc.1 for `~1.2`)
+	upper.Prerelease = "0"
+	return []versionComparison{{">=", lower}, {"<", upper}}
+}
+
+func (c versionComparison) matches(version SemVer) bool {
+	comparison := version.Compare(c.version)
+	switch c.operator {
+	case ">=":
+		return comparison >= 0
+	case "<=":
+		return comparison <= 0
+	case ">":
+		return comparison > 0
+	case "<":
+		return comparison < 0
+	case "!=":
+		return comparison != 0
+	}
+	return comparison == 0
+}
+
+func (c VersionConstraint) Matches(version SemVer) bool {
+	if c.literal != "" {
+		return version.String() == c.literal
+	}
+	prereleaseAllowed := version.Prerelease == ""
+	for _, comparison := range c.comparisons {
+		if !comparison.matches(version) {
+			return false
+		}
+		bound := comparison.version
+		if bound.Prerelease != "" && bound.Major == version.Major &&
+			bound.Minor == version.Minor && bound.Patch == version.Patch {
+			prereleaseAllowed = true
+		}
+	}
+	return prereleaseAllowed
+}
+
+// Whether the constraint is a single exact version, which needs
+// no resolution
+func (c VersionConstraint) IsExact() bool {
+	return c.literal != ""
+}
+
+// Exact version, without operator (e.g., 1.2.3 for `=1.2.3`), when
+// the constraint is a single exact version (see IsExact()), otherwise
+// an empty string
+func (c VersionConstraint) ExactVersion() string {
+	return c.literal
+}
+
+/**
+ * Highest of the versions matching the constraint. The versions which
+ * are not semantic ones are ignored, but for a literal version, which
+ * has to be one of the versions
+ */
+func (c VersionConstraint) Resolve(versions []string) (string, error) {
+	if c.literal != "" {
+		for _, version := range versions {
+			if version == c.literal {
+				return version, nil
+			}
+		}
+		return "", fmt.Errorf("none of the %d version(s) is the %s version",
+			len(versions), c.literal)
+	}
+
+	resolved, found := "", false
+	highest := SemVer{}
+	for _, version := range versions {
+		semVer, err := ParseSemVer(version)
+		if err != nil || !c.Matches(semVer) {
+			continue
+		}
+		if !found || semVer.Compare(highest) > 0 {
+			resolved, highest, found = version, semVer, true
+		}
+	}
+
+	if !found {
+		return "", fmt.Errorf("none of the %d version(s) matches the %s constraint",
+			len(versions), c.Constraint)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,78 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/semver_test.go
+//
+package utilities
+
+import (
+	"testing"
+)
+
+/**
+ * Check that the version constraints are resolved to the highest
+ * matching version, the pre-releases being skipped unless asked for
+ */
+func TestVersionConstraint(t *testing.T) {
+	versions := []string{"0.0.1", "0.0.3", "0.0.2", "0.1.0", "0.1.4",
+		"0.2.0-rc.1", "1.0.0", "1.2.0", "2.0.0-rc.1", "not-a-version", "1.0",
+		"1.0-SNAPSHOT", "1.2.3.post1"}
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"0.0.2", "0.0.2"},
+		{"=0.1.0", "0.1.0"},
+		{"~0.0.1", "0.0.3"},
+		{"~0", "0.1.4"},
+		{"^0.0.1", "0.0.1"},
+		{"^0.1.0", "0.1.4"},
+		{"^1.0.0", "1.2.0"},
+		{">=0.1,<0.2", "0.1.4"},
+		{">= 0.1, < 0.2", "0.1.4"},
+		{">=0.1 <1", "0.1.4"},
+		{"~0.1,!=0.1.4", "0.1.0"},
+		{"!= 1.2.0", "1.0.0"},
+		// A version without operator is taken literally
+		{"0.1", ""},
+		{"1.0", "1.0"},
+		{"1.0-SNAPSHOT", "1.0-SNAPSHOT"},
+		{"1.2.3.post1", "1.2.3.post1"},
+		{">=0.2.0-rc.1,<0.3", "0.2.0-rc.1"},
+		{"latest", "1.2.0"},
+		{">=3", ""},
+	}
+	for _, test := range tests {
+		constraint, err := ParseVersionConstraint(test.constraint)
+		if err != nil {
+			t.Errorf(`ParseVersionConstraint(%q) = %v`,
+				test.constraint, err)
+			continue
+		}
+		resolved, err := constraint.Resolve(versions)
+		if resolved != test.expected || (test.expected == "") != (err != nil) {
+			t.Errorf(`VersionConstraint(%q).Resolve() = %q, %v, expected %q`,
+				test.constraint, resolved, err, test.expected)
+		}
+	}
+
+	// The exact versions are given without their operator, if any
+	exact := map[string]string{"1.0": "1.0", "1.0-SNAPSHOT": "1.0-SNAPSHOT",
+		"1.2.3.post1": "1.2.3.post1", "=1.2.3": "1.2.3", "= 1.2.3": "1.2.3",
+		"~1.0": "", "^1.0": "", ">=1.0": "", "1.0,<2": "", "!=1.0": "",
+		"latest": ""}
+	for constraint, expected := range exact {
+		versionConstraint, err := ParseVersionConstraint(constraint)
+		if err != nil || versionConstraint.IsExact() != (expected != "") ||
+			versionConstraint.ExactVersion() != expected {
+			t.Errorf(`ParseVersionConstraint(%q).ExactVersion() = %q, %v, expected %q`,
+				constraint, versionConstraint.ExactVersion(), err, expected)
+		}
+	}
+
+	for _, invalid := range []string{"", "~", ">=x", "latest,1", "1.0 latest"} {
+		_, err := ParseVersionConstraint(invalid)
+		if err == nil {
+			t.Errorf(`ParseVersionConstraint(%q) = nil, expected an error`,
+				invalid)
+		}
+	}
+}
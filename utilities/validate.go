@@ -0,0 +1,406 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/validate.go
+//
+package utilities
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Error found while validating a deployment specification, at a given
+// position (line and column) of the YAML file
+type ValidationError struct {
+	File    string `json:"file,omitempty" yaml:"file,omitempty"`
+	Line    int    `json:"line" yaml:"line"`
+	Column  int    `json:"column" yaml:"column"`
+	Path    string `json:"path" yaml:"path"`
+	Message string `json:"message" yaml:"message"`
+}
+
+func (e ValidationError) Error() string {
+	position := fmt.Sprintf("%d:%d", e.Line, e.Column)
+	if e.File != "" {
+		position = e.File + ":" + position
+	}
+	if e.Path == "" {
+		return fmt.Sprintf("%s: %s", position, e.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", position, e.Path, e.Message)
+}
+
+// Checks of the format of the values, referred to by the `validate`
+// struct tags of the specification (e.g., `validate:"required,region"`)
+var formatValidators = map[string]func(string) error{
+	"region":     validateRegion,
+	"account_id": validateAccountId,
+	"semver":     validateSemVer,
+	"constraint": validateVersionConstraint,
+	"bucket":     validateBucketName,
+	"regex":      validateRegex,
+}
+
+var (
+	regionRegex    = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]+$`)
+	accountIdRegex = regexp.MustCompile(`^[0-9]{12}$`)
+	bucketRegex    = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+	ipAddressRegex = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+\.[0-9]+$`)
+	yamlLineRegex  = regexp.MustCompile(`line ([0-9]+)`)
+)
+
+// AWS region names, e.g., eu-west-1 or us-gov-west-1
+func validateRegion(region string) error {
+	if !regionRegex.MatchString(region) {
+		return fmt.Errorf("%q is not a region name (e.g., eu-west-1)", region)
+	}
+	return nil
+}
+
+// AWS account IDs are made of 12 digits
+func validateAccountId(accountId string) error {
+	if !accountIdRegex.MatchString(accountId) {
+		return fmt.Errorf("%q is not an account ID (12 digits)", accountId)
+	}
+	return nil
+}
+
+func validateSemVer(version string) error {
+	_, err := ParseSemVer(version)
+	return err
+}
+
+func validateVersionConstraint(constraint string) error {
+	_, err := ParseVersionConstraint(constraint)
+	return err
+}
+
+/**
+ * AWS S3 bucket naming rules
+ * Reference: https://docs.aws.amazon.com/AmazonS3/latest/userguide/bucketnamingrules.html
+ */
+func validateBucketName(bucketName string) error {
+	switch {
+	case !bucketRegex.MatchString(bucketName):
+		return fmt.Errorf("%q is not a bucket name (3 to 63 lowercase letters, digits, dots and hyphens, beginning and ending with a letter or a digit)",
+			bucketName)
+	case strings.Contains(bucketName, ".."):
+		return fmt.Errorf("%q is not a bucket name (two adjacent dots)",
+			bucketName)
+	case ipAddressRegex.MatchString(bucketName):
+		return fmt.Errorf("%q is not a bucket name (formatted as an IP address)",
+			bucketName)
+	case strings.HasPrefix(bucketName, "xn--"),
+		strings.HasPrefix(bucketName, "sthree-"):
+		return fmt.Errorf("%q is not a bucket name (reserved prefix)",
+			bucketName)
+	case strings.HasSuffix(bucketName, "-s3alias"),
+		strings.HasSuffix(bucketName, "--ol-s3"):
+		return fmt.Errorf("%q is not a bucket name (reserved suffix)",
+			bucketName)
+	}
+	return nil
+}
+
+func validateRegex(pattern string) error {
+	_, err := regexp.Compile(pattern)
+	return err
+}
+
+// Field of the specification, as known from the struct tags
+type specField struct {
+	Name     string
+	Type     reflect.Type
+	Required bool
+	// Whether the field may be inherited from the `defaults` block
+	Inheritable bool
+	Formats     []string
+	Enum        []string
+	Description string
+}
+
+// Fields of a struct of the specification, in the order of the struct,
+// the inline structs (e.g., CloudLocation) being flattened
+func specFields(structType reflect.Type) []specField {
+	fields := []specField{}
+	for idx := 0; idx < structType.NumField(); idx++ {
+		structField := structType.Field(idx)
+		name, options, _ := strings.Cut(structField.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(options, "inline") {
+			fields = append(fields, specFields(structField.Type)...)
+			continue
+		}
+		if !structField.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(structField.Name)
+		}
+
+		field := specField{Name: name, Type: structField.Type,
+			Description: structField.Tag.Get("desc")}
+		if enum := structField.Tag.Get("enum"); enum != "" {
+			field.Enum = strings.Split(enum, ",")
+		}
+		for _, rule := range strings.Split(structField.Tag.Get("validate"), ",") {
+			switch rule {
+			case "":
+			case "required":
+				field.Required = true
+			case "inheritable":
+				field.Inheritable = true
+			default:
+				field.Formats = append(field.Formats, rule)
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func isEnumValue(value string, enum []string) bool {
+	for _, enumValue := range enum {
+		if value == enumValue {
+			return true
+		}
+	}
+	return false
+}
+
+func isNullNode(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null"
+}
+
+func joinSpecPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// Walk over the YAML nodes of a specification, along with the types
+// of the SpecFile structure, collecting the errors
+type specValidator struct {
+	// Source file of the nodes, when the specification is layered
+	sources map[*yaml.Node]string
+	// Nodes inherited from the `defaults` block, validated there only
+	inherited map[*yaml.Node]bool
+	errors    []ValidationError
+}
+
+func (v *specValidator) addError(node *yaml.Node, path string,
+	format string, args ...interface{}) {
+	v.errors = append(v.errors, ValidationError{
+		File:    v.sources[node],
+		Line:    node.Line,
+		Column:  node.Column,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *specValidator) validateNode(node *yaml.Node, nodeType reflect.Type,
+	path string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if isNullNode(node) {
+		return
+	}
+	if nodeType.Kind() == reflect.Pointer {
+		nodeType = nodeType.Elem()
+	}
+
+	switch nodeType.Kind() {
+	case reflect.Struct:
+		v.validateMapping(node, nodeType, path)
+
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			v.addError(node, path, "a sequence is expected")
+			return
+		}
+		for idx, item := range node.Content {
+			v.validateNode(item, nodeType.Elem(),
+				fmt.Sprintf("%s[%d]", path, idx))
+		}
+
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			v.addError(node, path, "a mapping is expected")
+			return
+		}
+		for idx := 0; idx+1 < len(node.Content); idx += 2 {
+			v.validateNode(node.Content[idx+1], nodeType.Elem(),
+				joinSpecPath(path, node.Content[idx].Value))
+		}
+
+	default:
+		if node.Kind != yaml.ScalarNode {
+			v.addError(node, path, "a scalar value is expected")
+			return
+		}
+		err := node.Decode(reflect.New(nodeType).Interface())
+		if err != nil {
+			v.addError(node, path, "%q is not a valid %s value", node.Value,
+				nodeType.Kind())
+		}
+	}
+}
+
+func (v *specValidator) validateMapping(node *yaml.Node,
+	structType reflect.Type, path string) {
+	if node.Kind != yaml.MappingNode {
+		v.addError(node, path, "a mapping is expected")
+		return
+	}
+
+	fields := specFields(structType)
+	fieldsByName := map[string]specField{}
+	fieldNames := []string{}
+	for _, field := range fields {
+		fieldsByName[field.Name] = field
+		fieldNames = append(fieldNames, field.Name)
+	}
+	sort.Strings(fieldNames)
+
+	seen := map[string]bool{}
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		keyNode, valueNode := node.Content[idx], node.Content[idx+1]
+		keyPath := joinSpecPath(path, keyNode.Value)
+		if seen[keyNode.Value] {
+			v.addError(keyNode, keyPath, "duplicate field")
+			continue
+		}
+		seen[keyNode.Value] = true
+
+		field, found := fieldsByName[keyNode.Value]
+		if !found {
+			v.addError(keyNode, keyPath, "unknown field (known fields: %s)",
+				strings.Join(fieldNames, ", "))
+			continue
+		}
+		if v.inherited[valueNode] {
+			continue
+		}
+		v.validateNode(valueNode, field.Type, keyPath)
+		v.validateValue(valueNode, field, keyPath)
+	}
+
+	for _, field := range fields {
+		if field.Required && !seen[field.Name] {
+			v.addError(node, joinSpecPath(path, field.Name),
+				"missing required field")
+		}
+	}
+}
+
+func (v *specValidator) validateValue(node *yaml.Node, field specField,
+	path string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if isNullNode(node) || (node.Kind == yaml.ScalarNode && node.Value == "") ||
+		(node.Kind == yaml.SequenceNode && len(node.Content) == 0) {
+		if field.Required {
+			v.addError(node, path, "must not be empty")
+		}
+		return
+	}
+	if node.Kind != yaml.ScalarNode {
+		return
+	}
+
+	if len(field.Enum) > 0 && !isEnumValue(node.Value, field.Enum) {
+		v.addError(node, path, "%q is not one of: %s", node.Value,
+			strings.Join(field.Enum, ", "))
+	}
+
+	for _, format := range field.Formats {
+		validator, found := formatValidators[format]
+		if !found {
+			panic(fmt.Sprintf("the %s validation format is not known", format))
+		}
+		if err := validator(node.Value); err != nil {
+			v.addError(node, path, "%v", err)
+		}
+	}
+}
+
+/**
+ * Validate a deployment specification, i.e., check that:
+ *   + there is no unknown (e.g., misspelled) nor duplicate field
+ *   + the values have the expected types (mappings, scalars)
+ *   + the required fields are specified, for every section
+ *   + the values have the expected formats (e.g., region names,
+ *     account IDs, semantic versions, bucket names, regular expressions)
+ *     and, for the enumerations (e.g., provider), one of the known values
+ *   + the versions of Spark, of Delta Lake and of the compute engine
+ *     fit together (see validateCompatibility())
+ * Those are the rules of the JSON Schema (but for the compatibility) of the specification
+ * (see GenerateSpecSchema()), as both are derived from the struct tags
+ * Every error gives the line and column of the offending YAML node
+ */
+func ValidateSpec(content []byte) []ValidationError {
+	var document yaml.Node
+	err := yaml.Unmarshal(content, &document)
+	if err != nil {
+		validationError := ValidationError{Message: err.Error()}
+		if match := yamlLineRegex.FindStringSubmatch(err.Error()); match != nil {
+			validationError.Line, _ = strconv.Atoi(match[1])
+		}
+		return []ValidationError{validationError}
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return []ValidationError{{Message: "empty specification"}}
+	}
+
+	return validateSpecNode(document.Content[0], nil, nil, nil)
+}
+
+/**
+ * Validate a specification made of one or several layered files
+ * (see SpecLayers). The merged specification is validated, every error
+ * giving the file where the offending value comes from. The values
+ * inherited from the `defaults` block are validated once, in that block
+ */
+func ValidateSpecFile(specFilepaths ...string) ([]ValidationError, error) {
+	layers, err := LoadSpecLayers(specFilepaths...)
+	if err != nil {
+		return nil, err
+	}
+	return validateSpecNode(layers.Root, layers.Sources, layers.Inherited,
+		layers.Files), nil
+}
+
+func validateSpecNode(root *yaml.Node, sources map[*yaml.Node]string,
+	inherited map[*yaml.Node]bool, files []string) []ValidationError {
+	validator := &specValidator{sources: sources, inherited: inherited}
+	validator.validateNode(root, reflect.TypeOf(SpecFile{}), "")
+	validator.validateCompatibility(root, compatibilityTable)
+
+	// The errors are reported in the order of the files
+	fileRanks := map[string]int{}
+	for rank, file := range files {
+		fileRanks[file] = rank
+	}
+	sort.SliceStable(validator.errors, func(i, j int) bool {
+		errI, errJ := validator.errors[i], validator.errors[j]
+		if errI.File != errJ.File {
+			return fileRanks[errI.File] < fileRanks[errJ.File]
+		}
+		if errI.Line != errJ.Line {
+			return errI.Line < errJ.Line
+		}
+		return errI.Column < errJ.Column
+	})
+	return validator.errors
+}
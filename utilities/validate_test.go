@@ -0,0 +1,91 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/utilities/validate_test.go
+//
+package utilities
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+/**
+ * Check that the sample deployment specification file is valid, and that
+ * the errors of an invalid specification are reported with their position
+ */
+func TestValidateSpec(t *testing.T) {
+	validationErrors, err := ValidateSpecFile("../depl/aws-dev-sample.yaml")
+	if err != nil || len(validationErrors) > 0 {
+		t.Fatalf(`ValidateSpecFile() = %v, %v, expected no error`,
+			validationErrors, err)
+	}
+
+	spec := `metadata:
+  env: dev
+  projet: example-project
+storage_container:
+  provider: aws
+  region: eu-west-1
+  acct_id: 123456789
+  name: Example_Bucket
+airflow:
+  dag:
+    name_pattern: "example-("
+`
+	expected := []string{
+		"1:1: artifact_repo: missing required field",
+		"1:1: container: missing required field",
+		"1:1: container_repo: missing required field",
+		"2:3: metadata.project: missing required field",
+		"3:3: metadata.projet: unknown field",
+		"7:12: storage_container.acct_id: \"123456789\" is not an account ID",
+		"8:9: storage_container.name: \"Example_Bucket\" is not a bucket name",
+		"10:3: airflow.provider: missing required field",
+		"11:19: airflow.dag.name_pattern: error parsing regexp",
+	}
+	validationErrors = ValidateSpec([]byte(spec))
+	messages := []string{}
+	for _, validationError := range validationErrors {
+		messages = append(messages, validationError.Error())
+	}
+	for _, expectedMessage := range expected {
+		found := false
+		for _, message := range messages {
+			found = found || strings.HasPrefix(message, expectedMessage)
+		}
+		if !found {
+			t.Errorf(`ValidateSpec() = %q, expected an error starting with %q`,
+				messages, expectedMessage)
+		}
+	}
+}
+
+/**
+ * Check that an invalid value of the `defaults` block is reported once,
+ * where it is written, and not once per section inheriting it
+ */
+func TestValidateSpecDefaults(t *testing.T) {
+	sample, err := os.ReadFile("../depl/aws-dev-sample.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := strings.Replace(string(sample), "defaults:\n  provider: aws\n  region: eu-west-1",
+		"defaults:\n  provider: aws\n  region: eu-westish", 1)
+	specFilepath := filepath.Join(t.TempDir(), "spec.yaml")
+	err = os.WriteFile(specFilepath, []byte(spec), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validationErrors, err := ValidateSpecFile(specFilepath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "defaults.region: \"eu-westish\" is not a region"
+	if len(validationErrors) != 1 || validationErrors[0].Line != 14 ||
+		!strings.HasPrefix(validationErrors[0].Path+": "+validationErrors[0].Message, expected) {
+		t.Errorf(`ValidateSpecFile() = %v, expected a single error at line 14: %s`,
+			validationErrors, expected)
+	}
+}
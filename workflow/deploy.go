@@ -0,0 +1,325 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/deploy.go
+//
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/data-engineering-helpers/dppctl/report"
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+const (
+	// MWAA re-parses the DAG folder on a regular basis (every 30 seconds
+	// by default), so a few attempts are needed before the freshly
+	// uploaded DAGs appear
+	dagPollAttempts = 10
+	dagPollInterval = 30 * time.Second
+)
+
+/**
+ * Deploy the modules and the DAG files of the deployment specification,
+ * the versions of which have to be compatible (see
+ * utilities.CheckCompatibility()).
+ * In locked mode (i.e., given a lock, see Lock), the deployment is
+ * refused, before anything is uploaded, when the live state differs
+ * from the lock
+ */
+func Deploy(ctx context.Context, deplSpec utilities.SpecFile,
+	lock *Lock) (DeployReport, error) {
+	deployReport := DeployReport{}
+
+	// Versions of Spark, of Delta Lake and of the compute engine which
+	// do not fit together are refused, before any call to the services
+	err := utilities.CheckCompatibility(deplSpec)
+	if err != nil {
+		return deployReport, fmt.Errorf("the versions are not compatible: %w",
+			err)
+	}
+
+	// Acting with the credentials of another account than the one
+	// of the specification is refused
+	_, err = Preflight(ctx, deplSpec)
+	if err != nil {
+		return deployReport, err
+	}
+
+	// The resolved versions are used for the packages and the image tags
+	deplSpec, err = ResolveVersions(ctx, deplSpec)
+	if err != nil {
+		return deployReport, err
+	}
+
+	// /////////////////////////////////
+	// Lock - the live state has to be the locked one
+	// /////////////////////////////////
+	if lock != nil {
+		observed, err := observeLock(ctx, deplSpec)
+		if err != nil {
+			return deployReport, err
+		}
+		deployReport.LockDifferences = DiffLock(*lock, observed)
+		if len(deployReport.LockDifferences) > 0 {
+			return deployReport, lockError(deployReport.LockDifferences)
+		}
+		log.Println("The live state matches the lock")
+	}
+
+	// /////////////////////////////////
+	// Selection of the service implementations
+	// /////////////////////////////////
+	artifactRepo, err := service.NewArtifactRepository(ctx, artifactRepoConfig(deplSpec))
+	if err != nil {
+		return deployReport, fmt.Errorf("artifact_repo: %w", err)
+	}
+	containerRegistry, err := service.NewContainerRegistry(ctx, containerRepoConfig(deplSpec))
+	if err != nil {
+		return deployReport, fmt.Errorf("container_repo: %w", err)
+	}
+	objectStorage, err := service.NewObjectStorage(ctx, airflowConfig(deplSpec))
+	if err != nil {
+		return deployReport, fmt.Errorf("airflow: %w", err)
+	}
+	orchestrator, err := service.NewOrchestrator(ctx, airflowConfig(deplSpec))
+	if err != nil {
+		return deployReport, fmt.Errorf("airflow: %w", err)
+	}
+
+	// /////////////////////////////////
+	// CodeArtifact and ECR - the package of every module has to be
+	// published already, and its image (if any) pushed already
+	// /////////////////////////////////
+	caDomainName := deplSpec.ArtifactRepo.Domain
+	caDomainOwner := deplSpec.ArtifactRepo.AccountId
+	caRepoName := deplSpec.ArtifactRepo.Name
+
+	deployReport.Modules = newModuleReports(deplSpec)
+	for idx, module := range deplSpec.Container.Modules {
+		moduleReport := &deployReport.Modules[idx]
+		packageName := module.Name
+		packageVersion := module.Version
+
+		pkgDetails, err := artifactRepo.DescribePackageVersion(ctx,
+			caDomainName, caDomainOwner, caRepoName,
+			deplSpec.PackageFormat(module.Format), module.Namespace, packageName,
+			packageVersion)
+		if err != nil {
+			return deployReport, fmt.Errorf("the %s package, in version %s, cannot be found in the %s CodeArtifact repository: %w",
+				packageName, packageVersion, caRepoName, err)
+		}
+		log.Println("Versioned package found within the CodeArtifact repository:",
+			report.FormatPackageVersionDetail(pkgDetails))
+		moduleReport.PackageVersion = &pkgDetails
+
+		if module.Image == "" {
+			continue
+		}
+		imageDetail, err := containerRegistry.DescribeImageTag(ctx,
+			module.Image, packageVersion)
+		if err != nil {
+			return deployReport, fmt.Errorf("the %s image tag of the %s module cannot be found in the %s ECR repository: %w",
+				packageVersion, packageName, module.Image, err)
+		}
+		log.Println("Image found within the ECR service:",
+			module.Image+":"+packageVersion,
+			report.FormatImageDetail(imageDetail))
+		moduleReport.Image = &imageDetail
+	}
+
+	// /////////////////////////////////
+	// AWS S3 - upload of the DAG files
+	// /////////////////////////////////
+	sourceDir := deplSpec.Airflow.Dag.SourceDir
+	bucketName := deplSpec.Airflow.StorageContainer.Name
+	bucketPrefix := deplSpec.Airflow.StorageContainer.Prefix
+
+	dagFiles, err := listDagFiles(sourceDir)
+	if err != nil {
+		return deployReport, err
+	}
+
+	// Files which Airflow is expected to parse, once uploaded
+	expectedDagFiles := []string{}
+	for _, dagFile := range dagFiles {
+		isDag, err := mayDefineDags(filepath.Join(sourceDir, dagFile))
+		if err != nil {
+			return deployReport, err
+		}
+		if isDag {
+			expectedDagFiles = append(expectedDagFiles, dagFile)
+		}
+	}
+
+	for _, dagFile := range dagFiles {
+		key := path.Join(bucketPrefix, dagFile)
+		source := filepath.Join(sourceDir, dagFile)
+		uri := fmt.Sprintf("s3://%s/%s", bucketName, key)
+		etag, err := objectStorage.Upload(ctx, bucketName, key, source)
+		if err != nil {
+			return deployReport, fmt.Errorf("the %s DAG file cannot be uploaded onto %s: %w",
+				dagFile, uri, err)
+		}
+		log.Println("Uploaded:", uri, "ETag="+etag)
+		deployReport.Uploaded = append(deployReport.Uploaded,
+			UploadedObject{source, uri, etag})
+	}
+
+	// /////////////////////////////////
+	// MWAA/Airflow - the uploaded DAGs have to be picked up by Airflow
+	// /////////////////////////////////
+	deployReport.Dags, err = waitForDags(ctx, orchestrator,
+		deplSpec.Airflow.Domain, deplSpec.Airflow.Dag.NamePattern,
+		expectedDagFiles, dagPollAttempts, dagPollInterval)
+	return deployReport, err
+}
+
+/**
+ * Wait for Airflow to parse every one of the given DAG files (paths
+ * relative to the DAG folder), so that the DAGs which were already
+ * there before the upload do not count, and for a DAG to match
+ * the name pattern. The DAGs matching the name pattern are returned.
+ * When the attempts are exhausted, the last error (e.g., of the listing
+ * of the DAGs) is wrapped into the returned one
+ */
+func waitForDags(ctx context.Context, orchestrator service.Orchestrator,
+	environment string, namePattern string, dagFiles []string,
+	attempts int, interval time.Duration) ([]utilities.MwaaDagMetadata,
+	error) {
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		dagMetadataList, err := orchestrator.ListDags(ctx, environment)
+		if err == nil {
+			dagList, err := utilities.ExtractMatchingAWSMWAADagList(
+				dagMetadataList, namePattern)
+			if err != nil {
+				return nil, err
+			}
+			missing := missingDagFiles(dagMetadataList, dagFiles)
+			switch {
+			case len(missing) > 0:
+				lastErr = fmt.Errorf("%d uploaded DAG file(s) not parsed yet: %s",
+					len(missing), strings.Join(missing, ", "))
+			case len(dagList) == 0:
+				lastErr = fmt.Errorf("no Airflow DAG matching the %s name pattern yet",
+					namePattern)
+			default:
+				log.Printf("%d MWAA/Airflow DAG(s) matching the name pattern, the %d uploaded DAG file(s) being parsed",
+					len(dagList), len(dagFiles))
+				return dagList, nil
+			}
+		} else {
+			lastErr = err
+		}
+
+		log.Printf("The uploaded DAGs are not picked up by Airflow yet (attempt %d/%d): %v",
+			attempt, attempts, lastErr)
+		if attempt < attempts {
+			// The wait is interrupted when the context is cancelled
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("the uploaded DAGs were not picked up by the %s MWAA environment after %d attempts: %w",
+		environment, attempts, lastErr)
+}
+
+// DAG files (paths relative to the DAG folder) which are the file
+// of none of the DAGs. Airflow gives the path of the file either relative
+// to the DAG folder, or absolute
+func missingDagFiles(dagMetadataList []utilities.MwaaDagMetadata,
+	dagFiles []string) []string {
+	missing := []string{}
+	for _, dagFile := range dagFiles {
+		found := false
+		for _, dag := range dagMetadataList {
+			dagFilepath := strings.TrimPrefix(dag.Filepath, "/")
+			found = found || dagFilepath == dagFile ||
+				strings.HasSuffix(dagFilepath, "/"+dagFile)
+		}
+		if !found {
+			missing = append(missing, dagFile)
+		}
+	}
+	return missing
+}
+
+// Whether Airflow would look for DAGs in the file: as its DAG discovery
+// safe mode does, a Python file mentioning both `airflow` and `dag`
+// (other files, e.g., SQL scripts, are only uploaded)
+func mayDefineDags(filePath string) (bool, error) {
+	if filepath.Ext(filePath) != ".py" {
+		return false, nil
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return false, err
+	}
+	lowerContent := bytes.ToLower(content)
+	return bytes.Contains(lowerContent, []byte("airflow")) &&
+		bytes.Contains(lowerContent, []byte("dag")), nil
+}
+
+// Retrieve the paths (relative to the source directory) of the DAG files.
+// Hidden files and directories (e.g., .git, .DS_Store) are skipped
+func listDagFiles(sourceDir string) ([]string, error) {
+	dagFiles := []string{}
+
+	if sourceDir == "" {
+		return dagFiles, errors.New("empty DAG source directory")
+	}
+
+	err := filepath.WalkDir(sourceDir,
+		func(filePath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(entry.Name(), ".") && filePath != sourceDir {
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if entry.IsDir() {
+				return nil
+			}
+
+			relPath, err := filepath.Rel(sourceDir, filePath)
+			if err != nil {
+				return err
+			}
+			dagFiles = append(dagFiles, filepath.ToSlash(relPath))
+			return nil
+		})
+
+	return dagFiles, err
+}
+
+// Retrieve, through the orchestrator, the DAGs, for which the name is
+// matching the given pattern
+func listMatchingDags(ctx context.Context, orchestrator service.Orchestrator,
+	environment string, namePattern string) ([]utilities.MwaaDagMetadata,
+	error) {
+	dagMetadataList, err := orchestrator.ListDags(ctx, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	return utilities.ExtractMatchingAWSMWAADagList(dagMetadataList,
+		namePattern)
+}
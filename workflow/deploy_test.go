@@ -0,0 +1,117 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/deploy_test.go
+//
+package workflow
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+// Orchestrator giving, at every call, the next of the listings, the last
+// one being repeated
+type fakeOrchestrator struct {
+	listings [][]utilities.MwaaDagMetadata
+	errs     []error
+	calls    int
+}
+
+func (o *fakeOrchestrator) ListDags(ctx context.Context,
+	environment string) ([]utilities.MwaaDagMetadata, error) {
+	idx := o.calls
+	if idx >= len(o.listings) {
+		idx = len(o.listings) - 1
+	}
+	o.calls++
+	return o.listings[idx], o.errs[idx]
+}
+
+/**
+ * Check that the deployment waits for every uploaded DAG file to be
+ * parsed, whatever the DAGs already there, and that the last error
+ * is kept once the attempts are exhausted
+ */
+func TestWaitForDags(t *testing.T) {
+	previous := utilities.MwaaDagMetadata{DagId: "example_old",
+		Filepath: "old.py"}
+	uploaded := utilities.MwaaDagMetadata{DagId: "example_new",
+		Filepath: "/usr/local/airflow/dags/sub/new.py"}
+	accessDenied := &service.Error{Service: "mwaa", Operation: "ListDags",
+		Kind: service.ErrAccessDenied, Err: errors.New("example error")}
+	tests := []struct {
+		orchestrator  *fakeOrchestrator
+		expectedDags  int
+		expectedCalls int
+		expectedErr   string
+	}{
+		// The DAG of the uploaded file appears at the second attempt
+		{&fakeOrchestrator{
+			listings: [][]utilities.MwaaDagMetadata{{previous},
+				{previous, uploaded}},
+			errs: []error{nil, nil}}, 2, 2, ""},
+		// Only the DAG already there matches the name pattern
+		{&fakeOrchestrator{
+			listings: [][]utilities.MwaaDagMetadata{{previous}},
+			errs:     []error{nil}}, 0, 3,
+			"1 uploaded DAG file(s) not parsed yet: sub/new.py"},
+		{&fakeOrchestrator{
+			listings: [][]utilities.MwaaDagMetadata{nil},
+			errs:     []error{accessDenied}}, 0, 3, "access denied"},
+	}
+	for _, test := range tests {
+		dags, err := waitForDags(context.Background(), test.orchestrator,
+			"example-env", "example", []string{"sub/new.py"}, 3, 0)
+		if len(dags) != test.expectedDags ||
+			test.orchestrator.calls != test.expectedCalls ||
+			(test.expectedErr == "") != (err == nil) ||
+			(err != nil && !strings.Contains(err.Error(), test.expectedErr)) {
+			t.Errorf(`waitForDags() = %v, %v, with %d call(s), expected %d DAG(s), %q, with %d call(s)`,
+				dags, err, test.orchestrator.calls, test.expectedDags,
+				test.expectedErr, test.expectedCalls)
+		}
+	}
+
+	orchestrator := &fakeOrchestrator{
+		listings: [][]utilities.MwaaDagMetadata{nil},
+		errs:     []error{accessDenied}}
+	_, err := waitForDags(context.Background(), orchestrator, "example-env",
+		"example", []string{"sub/new.py"}, 2, 0)
+	if !errors.Is(err, service.ErrAccessDenied) {
+		t.Errorf(`waitForDags() = %v, expected the access denied error to be wrapped`,
+			err)
+	}
+}
+
+/**
+ * Check that only the Python files mentioning Airflow and DAGs are
+ * expected to be parsed by Airflow
+ */
+func TestMayDefineDags(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"example.py": "from airflow import DAG\n",
+		"helper.py":  "def helper():\n    return 1\n",
+		"query.sql":  "-- airflow dag\nSELECT 1;\n",
+	}
+	expected := map[string]bool{"example.py": true, "helper.py": false,
+		"query.sql": false}
+	for name, content := range files {
+		filePath := filepath.Join(dir, name)
+		err := os.WriteFile(filePath, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		isDag, err := mayDefineDags(filePath)
+		if err != nil || isDag != expected[name] {
+			t.Errorf(`mayDefineDags(%s) = %t, %v, expected %t`, name, isDag,
+				err, expected[name])
+		}
+	}
+}
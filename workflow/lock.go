@@ -0,0 +1,363 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/lock.go
+//
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+// Name of the lock file, written next to the specification
+const LockFilename = "dppctl.lock"
+
+// Header of the lock file
+const lockHeader = "# Written by `dppctl -c check`: do not edit it by hand\n"
+
+/**
+ * Lock of the state observed by a successful check: resolved versions
+ * and revisions of the packages, hashes of their assets, digests of
+ * the images and MD5 of the local DAG files (i.e., of the files
+ * the deployment uploads). In locked mode, the deployment is refused
+ * when the live state differs from the lock (e.g., an image tag pushed
+ * again, as the tags of ECR may be mutable, or a DAG file changed since
+ * the check)
+ */
+type Lock struct {
+	Project string         `json:"project" yaml:"project"`
+	Env     string         `json:"env" yaml:"env"`
+	Modules []LockedModule `json:"modules" yaml:"modules"`
+	Dags    LockedDags     `json:"dags" yaml:"dags"`
+}
+
+// Version of the package of a module, and its image, if any
+type LockedModule struct {
+	Name       string        `json:"name" yaml:"name"`
+	Namespace  string        `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Constraint string        `json:"constraint,omitempty" yaml:"constraint,omitempty"`
+	Version    string        `json:"version" yaml:"version"`
+	Format     string        `json:"format" yaml:"format"`
+	Revision   string        `json:"revision" yaml:"revision"`
+	Assets     []LockedAsset `json:"assets" yaml:"assets"`
+	Image      *LockedImage  `json:"image,omitempty" yaml:"image,omitempty"`
+}
+
+type LockedAsset struct {
+	Name   string `json:"name" yaml:"name"`
+	SHA256 string `json:"sha256" yaml:"sha256"`
+}
+
+type LockedImage struct {
+	Repository string `json:"repository" yaml:"repository"`
+	Tag        string `json:"tag" yaml:"tag"`
+	Digest     string `json:"digest" yaml:"digest"`
+}
+
+// DAG files of the local DAG directory, uploaded by the deployment
+// onto the DAG folder of Airflow. They are compared before the upload,
+// so that a deployment does not invalidate the lock
+type LockedDags struct {
+	SourceDir string       `json:"source_dir" yaml:"source_dir"`
+	Files     []LockedFile `json:"files" yaml:"files"`
+}
+
+type LockedFile struct {
+	Path string `json:"path" yaml:"path"`
+	MD5  string `json:"md5" yaml:"md5"`
+}
+
+// Default path of the lock file: next to the last (i.e., the most
+// specific) specification file
+func LockFilepath(specFilepaths []string) string {
+	if len(specFilepaths) == 0 {
+		return LockFilename
+	}
+	return filepath.Join(filepath.Dir(specFilepaths[len(specFilepaths)-1]),
+		LockFilename)
+}
+
+// Lock of the state reported by a check. The lists are sorted, so that
+// the lock file is stable from one run to the other
+func NewLock(deplSpec utilities.SpecFile, checkReport CheckReport) Lock {
+	lock := Lock{
+		Project: deplSpec.Metadata.Project,
+		Env:     deplSpec.Metadata.Env,
+		Modules: []LockedModule{},
+		Dags: LockedDags{SourceDir: checkReport.DagSourceDir,
+			Files: []LockedFile{}},
+	}
+
+	for _, moduleReport := range checkReport.Modules {
+		lockedModule := LockedModule{
+			Name:       moduleReport.Module,
+			Namespace:  moduleReport.Namespace,
+			Constraint: moduleReport.Constraint,
+			Version:    moduleReport.Version,
+			Format:     moduleReport.Format,
+			Assets:     []LockedAsset{},
+		}
+		if moduleReport.PackageVersion != nil {
+			lockedModule.Revision = moduleReport.PackageVersion.Revision
+		}
+		for _, asset := range moduleReport.Assets {
+			lockedModule.Assets = append(lockedModule.Assets,
+				LockedAsset{asset.Name, asset.Hashes[service.AssetHashSHA256]})
+		}
+		sort.Slice(lockedModule.Assets, func(i, j int) bool {
+			return lockedModule.Assets[i].Name < lockedModule.Assets[j].Name
+		})
+		if moduleReport.ImageRepo != "" {
+			lockedModule.Image = &LockedImage{Repository: moduleReport.ImageRepo,
+				Tag: moduleReport.Version}
+			if moduleReport.Image != nil {
+				lockedModule.Image.Digest = moduleReport.Image.Digest
+			}
+		}
+		lock.Modules = append(lock.Modules, lockedModule)
+	}
+
+	for _, dagFile := range checkReport.DagFiles {
+		lock.Dags.Files = append(lock.Dags.Files,
+			LockedFile{dagFile.Path, dagFile.MD5})
+	}
+	sort.Slice(lock.Dags.Files, func(i, j int) bool {
+		return lock.Dags.Files[i].Path < lock.Dags.Files[j].Path
+	})
+
+	return lock
+}
+
+func WriteLock(lockFilepath string, lock Lock) error {
+	var content bytes.Buffer
+	content.WriteString(lockHeader)
+	encoder := yaml.NewEncoder(&content)
+	encoder.SetIndent(2)
+	err := encoder.Encode(lock)
+	if err != nil {
+		return err
+	}
+	err = encoder.Close()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(lockFilepath, content.Bytes(), 0644)
+}
+
+func ReadLock(lockFilepath string) (Lock, error) {
+	lock := Lock{}
+	content, err := os.ReadFile(lockFilepath)
+	if err != nil {
+		return lock, fmt.Errorf("the %s lock file cannot be read (it is written by a successful check): %w",
+			lockFilepath, err)
+	}
+	err = yaml.Unmarshal(content, &lock)
+	if err != nil {
+		return lock, fmt.Errorf("the %s lock file cannot be decoded: %w",
+			lockFilepath, err)
+	}
+	return lock, nil
+}
+
+/**
+ * Compare the lock of a check with the lock file. The lock file is
+ * written instead when it is missing (unless it is required), or when
+ * an update is asked for (e.g., with the `-update-lock` option), so that
+ * a check never re-pins the lock by itself. It returns whether the lock
+ * file has been written and, otherwise, the differences with it
+ */
+func ApplyLock(lockFilepath string, lock Lock, update bool,
+	required bool) (bool, []string, error) {
+	if update {
+		return true, nil, WriteLock(lockFilepath, lock)
+	}
+
+	locked, err := ReadLock(lockFilepath)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return true, nil, WriteLock(lockFilepath, lock)
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	return false, DiffLock(locked, lock), nil
+}
+
+/**
+ * Differences between the locked state and the observed one, as
+ * messages. There is no difference when both states are the same
+ */
+func DiffLock(locked Lock, observed Lock) []string {
+	diffs := []string{}
+	if locked.Project != observed.Project || locked.Env != observed.Env {
+		diffs = append(diffs, fmt.Sprintf("the lock is for the %s project in the %s environment, not for the %s project in the %s environment",
+			locked.Project, locked.Env, observed.Project, observed.Env))
+	}
+
+	// Modules
+	observedModules := map[string]LockedModule{}
+	for _, module := range observed.Modules {
+		observedModules[module.Name] = module
+	}
+	lockedNames := map[string]bool{}
+	for _, lockedModule := range locked.Modules {
+		lockedNames[lockedModule.Name] = true
+		observedModule, found := observedModules[lockedModule.Name]
+		if !found {
+			diffs = append(diffs, fmt.Sprintf("the %s module is locked, but is no longer in the specification",
+				lockedModule.Name))
+			continue
+		}
+		diffs = append(diffs, diffLockedModule(lockedModule, observedModule)...)
+	}
+	for _, observedModule := range observed.Modules {
+		if !lockedNames[observedModule.Name] {
+			diffs = append(diffs, fmt.Sprintf("the %s module is not locked",
+				observedModule.Name))
+		}
+	}
+
+	// Local DAG files
+	if locked.Dags.SourceDir != observed.Dags.SourceDir {
+		diffs = append(diffs, fmt.Sprintf("the DAG files are locked from the %s directory, whereas they are taken from the %s directory",
+			locked.Dags.SourceDir, observed.Dags.SourceDir))
+	}
+	diffs = append(diffs, diffLockedEntries("DAG file",
+		lockedFiles(locked.Dags), lockedFiles(observed.Dags), "MD5")...)
+
+	return diffs
+}
+
+func diffLockedModule(locked LockedModule, observed LockedModule) []string {
+	diffs := []string{}
+	prefix := "the " + locked.Name + " module"
+	if locked.Version != observed.Version {
+		diffs = append(diffs, fmt.Sprintf("%s is locked in version %s, whereas the %s version is resolved",
+			prefix, locked.Version, observed.Version))
+		return diffs
+	}
+	if locked.Revision != observed.Revision {
+		diffs = append(diffs, fmt.Sprintf("%s is locked in revision %s, whereas the %s revision is published",
+			prefix, locked.Revision, observed.Revision))
+	}
+
+	lockedAssets, observedAssets := map[string]string{}, map[string]string{}
+	for _, asset := range locked.Assets {
+		lockedAssets[asset.Name] = asset.SHA256
+	}
+	for _, asset := range observed.Assets {
+		observedAssets[asset.Name] = asset.SHA256
+	}
+	diffs = append(diffs, diffLockedEntries(prefix+": asset", lockedAssets,
+		observedAssets, "SHA-256")...)
+
+	switch {
+	case locked.Image == nil && observed.Image == nil:
+	case locked.Image == nil || observed.Image == nil:
+		diffs = append(diffs, fmt.Sprintf("%s: the image is either locked or specified, not both",
+			prefix))
+	case *locked.Image != *observed.Image:
+		diffs = append(diffs, fmt.Sprintf("%s: the %s:%s image is locked with the %s digest, whereas it is %s:%s with the %s digest",
+			prefix, locked.Image.Repository, locked.Image.Tag,
+			locked.Image.Digest, observed.Image.Repository,
+			observed.Image.Tag, observed.Image.Digest))
+	}
+
+	return diffs
+}
+
+func lockedFiles(dags LockedDags) map[string]string {
+	files := map[string]string{}
+	for _, dagFile := range dags.Files {
+		files[dagFile.Path] = dagFile.MD5
+	}
+	return files
+}
+
+// Differences between locked and observed entries (e.g., assets, DAG
+// files), indexed by name, in the order of the names
+func diffLockedEntries(kind string, locked map[string]string,
+	observed map[string]string, valueName string) []string {
+	names := []string{}
+	for name := range locked {
+		names = append(names, name)
+	}
+	for name := range observed {
+		if _, found := locked[name]; !found {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	diffs := []string{}
+	for _, name := range names {
+		lockedValue, isLocked := locked[name]
+		observedValue, isObserved := observed[name]
+		switch {
+		case !isObserved:
+			diffs = append(diffs, fmt.Sprintf("%s %s is locked, but cannot be found",
+				kind, name))
+		case !isLocked:
+			diffs = append(diffs, fmt.Sprintf("%s %s is not locked", kind, name))
+		case lockedValue != observedValue:
+			diffs = append(diffs, fmt.Sprintf("%s %s is locked with the %s %s, whereas it is %s",
+				kind, name, valueName, lockedValue, observedValue))
+		}
+	}
+	return diffs
+}
+
+/**
+ * Lock of the live state, observed with the same calls as the check
+ * (package version, assets and image), and of the local DAG files
+ */
+func observeLock(ctx context.Context, deplSpec utilities.SpecFile) (Lock,
+	error) {
+	checkReport := CheckReport{Modules: newModuleReports(deplSpec)}
+	tasks := []task{
+		{"airflow", func(ctx context.Context) error {
+			return checkDagFiles(ctx, deplSpec, &checkReport)
+		}},
+	}
+	for idx, module := range deplSpec.Container.Modules {
+		module, moduleReport := module, &checkReport.Modules[idx]
+		tasks = append(tasks, task{"artifact_repo", func(ctx context.Context) error {
+			return checkPackageVersion(ctx, deplSpec, module, moduleReport)
+		}}, task{"artifact_repo", func(ctx context.Context) error {
+			return checkPackageAssets(ctx, deplSpec, module, moduleReport, nil)
+		}})
+		if module.Image != "" {
+			tasks = append(tasks, task{"container_repo", func(ctx context.Context) error {
+				return checkModuleImage(ctx, deplSpec, module, moduleReport)
+			}})
+		}
+	}
+
+	failures := []error{}
+	for idx, err := range runTasks(ctx, DefaultParallelism, tasks) {
+		if err != nil {
+			failures = append(failures,
+				fmt.Errorf("%s: %w", tasks[idx].Section, err))
+		}
+	}
+	if len(failures) > 0 {
+		return Lock{}, errors.Join(failures...)
+	}
+	return NewLock(deplSpec, checkReport), nil
+}
+
+// Error reporting the differences between the lock and the live state
+func lockError(diffs []string) error {
+	return fmt.Errorf("the live state differs from the lock (%d difference(s)):\n  - %s",
+		len(diffs), strings.Join(diffs, "\n  - "))
+}
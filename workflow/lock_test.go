@@ -0,0 +1,160 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/lock_test.go
+//
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+/**
+ * Check that the lock of a check is written and read back as is, and
+ * that the differences with the live state are reported
+ */
+func TestLock(t *testing.T) {
+	deplSpec := utilities.SpecFile{}
+	deplSpec.Metadata.Project = "example-project"
+	deplSpec.Metadata.Env = "dev"
+	checkReport := CheckReport{
+		Modules: []ModuleReport{{
+			Module: "example-pkg", Version: "0.0.3", Constraint: "~0.0.1",
+			Format:         "pypi",
+			PackageVersion: &service.PackageVersionDetail{Revision: "rev1"},
+			Assets: []service.PackageAsset{
+				{Name: "example_pkg-0.0.3.tar.gz",
+					Hashes: map[string]string{"SHA-256": "bbb"}},
+				{Name: "example_pkg-0.0.3-py3-none-any.whl",
+					Hashes: map[string]string{"SHA-256": "aaa"}},
+			},
+			ImageRepo: "example-repo",
+			Image:     &service.ImageDetail{Digest: "sha256:111"},
+		}},
+		DagSourceDir: "dags",
+		DagFiles:     []DagFile{{Path: "example.py", MD5: "e1"}},
+	}
+
+	lock := NewLock(deplSpec, checkReport)
+	lockFilepath := filepath.Join(t.TempDir(), LockFilename)
+	err := WriteLock(lockFilepath, lock)
+	if err != nil {
+		t.Fatalf(`WriteLock() = %v`, err)
+	}
+	locked, err := ReadLock(lockFilepath)
+	if err != nil {
+		t.Fatalf(`ReadLock() = %v`, err)
+	}
+	if diffs := DiffLock(locked, lock); len(diffs) > 0 {
+		t.Errorf(`DiffLock() = %q, expected no difference`, diffs)
+	}
+
+	// Image tag pushed again, asset uploaded again, a DAG file changed
+	// and a new DAG file
+	checkReport.Modules[0].Image = &service.ImageDetail{Digest: "sha256:222"}
+	checkReport.Modules[0].Assets[1].Hashes = map[string]string{"SHA-256": "ccc"}
+	checkReport.DagFiles = []DagFile{{Path: "example.py", MD5: "e2"},
+		{Path: "other.py", MD5: "e3"}}
+	expected := []string{
+		"the example-pkg module: asset example_pkg-0.0.3-py3-none-any.whl is locked with the SHA-256 aaa, whereas it is ccc",
+		"the example-pkg module: the example-repo:0.0.3 image is locked with the sha256:111 digest, whereas it is example-repo:0.0.3 with the sha256:222 digest",
+		"DAG file example.py is locked with the MD5 e1, whereas it is e2",
+		"DAG file other.py is not locked",
+	}
+	diffs := DiffLock(locked, NewLock(deplSpec, checkReport))
+	if strings.Join(diffs, "\n") != strings.Join(expected, "\n") {
+		t.Errorf(`DiffLock() = %q, expected %q`, diffs, expected)
+	}
+
+	// Another resolved version
+	checkReport.Modules[0].Version = "0.0.4"
+	diffs = DiffLock(locked, NewLock(deplSpec, checkReport))
+	if len(diffs) == 0 || !strings.Contains(diffs[0],
+		"is locked in version 0.0.3, whereas the 0.0.4 version is resolved") {
+		t.Errorf(`DiffLock() = %q, expected a version difference`,
+			diffs)
+	}
+}
+
+/**
+ * Check that the lock file is only written when it is missing, or when
+ * an update is asked for, and otherwise compared with the lock
+ */
+func TestApplyLock(t *testing.T) {
+	lockFilepath := filepath.Join(t.TempDir(), LockFilename)
+	lock := Lock{Project: "example-project", Env: "dev",
+		Modules: []LockedModule{{Name: "example-pkg", Version: "0.0.1",
+			Assets: []LockedAsset{}}}}
+
+	// Missing, but required: nothing is written
+	written, _, err := ApplyLock(lockFilepath, lock, false, true)
+	if written || err == nil {
+		t.Errorf(`ApplyLock(required) = %t, %v, expected an error`, written,
+			err)
+	}
+
+	written, diffs, err := ApplyLock(lockFilepath, lock, false, false)
+	if !written || len(diffs) > 0 || err != nil {
+		t.Errorf(`ApplyLock(missing) = %t, %q, %v, expected the lock file to be written`,
+			written, diffs, err)
+	}
+
+	// Another version is compared with the lock, but not locked
+	other := lock
+	other.Modules = []LockedModule{{Name: "example-pkg", Version: "0.0.2",
+		Assets: []LockedAsset{}}}
+	for _, required := range []bool{false, true} {
+		written, diffs, err = ApplyLock(lockFilepath, other, false, required)
+		if written || len(diffs) != 1 || err != nil {
+			t.Errorf(`ApplyLock(%t) = %t, %q, %v, expected a difference`,
+				required, written, diffs, err)
+		}
+	}
+
+	written, _, err = ApplyLock(lockFilepath, other, true, false)
+	locked, _ := ReadLock(lockFilepath)
+	if !written || err != nil || locked.Modules[0].Version != "0.0.2" {
+		t.Errorf(`ApplyLock(update) = %t, %v, locked %+v, expected the lock file to be updated`,
+			written, err, locked.Modules)
+	}
+}
+
+/**
+ * Check that the DAG files are locked from the local DAG directory,
+ * so that the lock still holds once they have been uploaded, and that
+ * a DAG file changed since the check is reported
+ */
+func TestObserveLockDagFiles(t *testing.T) {
+	deplSpec := utilities.SpecFile{}
+	deplSpec.Airflow.Dag.SourceDir = t.TempDir()
+	dagFilepath := filepath.Join(deplSpec.Airflow.Dag.SourceDir, "example.py")
+	err := os.WriteFile(dagFilepath, []byte("example"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locked, err := observeLock(context.Background(), deplSpec)
+	expected := []LockedFile{{"example.py", "1a79a4d60de6718e8e5b326e338ae533"}}
+	if err != nil || fmt.Sprint(locked.Dags.Files) != fmt.Sprint(expected) {
+		t.Fatalf(`observeLock() = %+v, %v, expected %v`, locked.Dags, err,
+			expected)
+	}
+
+	err = os.WriteFile(dagFilepath, []byte("changed example"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	observed, err := observeLock(context.Background(), deplSpec)
+	diffs := DiffLock(locked, observed)
+	if err != nil || len(diffs) != 1 ||
+		!strings.HasPrefix(diffs[0], "DAG file example.py is locked with the MD5") {
+		t.Errorf(`DiffLock() = %q, %v, expected the changed DAG file`, diffs,
+			err)
+	}
+}
@@ -0,0 +1,381 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/plan.go
+//
+package workflow
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+// Kind of change, in the same way as Terraform reports them. As the
+// deployment never removes anything, the resources which are observed
+// but no longer specified are reported as unmanaged, and left as is
+type PlanAction string
+
+const (
+	PlanAdd       PlanAction = "+"
+	PlanChange    PlanAction = "~"
+	PlanUnmanaged PlanAction = "?"
+)
+
+// A single difference between the desired and the observed states
+type PlanItem struct {
+	Action   PlanAction `json:"action" yaml:"action"`
+	Resource string     `json:"resource" yaml:"resource"`
+	Name     string     `json:"name" yaml:"name"`
+	Detail   string     `json:"detail" yaml:"detail"`
+}
+
+// Desired state, as derived from the deployment specification
+type DesiredState struct {
+	DagBucket string
+	DagPrefix string
+	// MD5 hex digest of the local DAG files, indexed by relative path
+	DagFiles map[string]string
+
+	PackageRepo string
+	Modules     []DesiredModule
+
+	AirflowEnv     string
+	DagNamePattern string
+}
+
+// Desired state of a module: its package has to be published and,
+// when ImageRepo is set, its image pushed (tagged with the version)
+type DesiredModule struct {
+	Name      string
+	Namespace string
+	Version   string
+	Format    string
+	ImageRepo string
+}
+
+// Observed state of a module, at the same index as in DesiredState.Modules
+type ObservedModule struct {
+	PackageVersionFound bool
+	ImageTagFound       bool
+}
+
+// Observed state, as read back from the cloud services
+type ObservedState struct {
+	// ETag of the DAG objects, indexed by path relative to the prefix
+	DagObjects map[string]string
+
+	Modules []ObservedModule
+
+	// DAGs matching the name pattern
+	Dags []utilities.MwaaDagMetadata
+}
+
+func BuildDesiredState(deplSpec utilities.SpecFile) (DesiredState, error) {
+	desired := DesiredState{
+		DagBucket:      deplSpec.Airflow.StorageContainer.Name,
+		DagPrefix:      deplSpec.Airflow.StorageContainer.Prefix,
+		DagFiles:       map[string]string{},
+		PackageRepo:    deplSpec.ArtifactRepo.Name,
+		AirflowEnv:     deplSpec.Airflow.Domain,
+		DagNamePattern: deplSpec.Airflow.Dag.NamePattern,
+	}
+	for _, module := range deplSpec.Container.Modules {
+		desired.Modules = append(desired.Modules, DesiredModule{
+			Name:      module.Name,
+			Namespace: module.Namespace,
+			Version:   module.Version,
+			Format:    deplSpec.PackageFormat(module.Format),
+			ImageRepo: module.Image,
+		})
+	}
+
+	dagFiles, err := localDagFiles(deplSpec.Airflow.Dag.SourceDir)
+	if err != nil {
+		return desired, err
+	}
+	for _, dagFile := range dagFiles {
+		desired.DagFiles[dagFile.Path] = dagFile.MD5
+	}
+
+	return desired, nil
+}
+
+func ObserveState(ctx context.Context, deplSpec utilities.SpecFile,
+	desired DesiredState) (ObservedState, error) {
+	observed := ObservedState{DagObjects: map[string]string{}}
+	dagPrefix := dagObjectPrefix(desired.DagPrefix)
+
+	// /////////////////////////////////
+	// Selection of the service implementations
+	// /////////////////////////////////
+	objectStorage, err := service.NewObjectStorage(ctx, airflowConfig(deplSpec))
+	if err != nil {
+		return observed, fmt.Errorf("airflow: %w", err)
+	}
+	artifactRepo, err := service.NewArtifactRepository(ctx, artifactRepoConfig(deplSpec))
+	if err != nil {
+		return observed, fmt.Errorf("artifact_repo: %w", err)
+	}
+	containerRegistry, err := service.NewContainerRegistry(ctx, containerRepoConfig(deplSpec))
+	if err != nil {
+		return observed, fmt.Errorf("container_repo: %w", err)
+	}
+	orchestrator, err := service.NewOrchestrator(ctx, airflowConfig(deplSpec))
+	if err != nil {
+		return observed, fmt.Errorf("airflow: %w", err)
+	}
+
+	// /////////////////////////////////
+	// Object storage (e.g., AWS S3)
+	// /////////////////////////////////
+	err = objectStorage.List(ctx, desired.DagBucket, dagPrefix,
+		service.ListOptions{}, func(object service.S3Object) error {
+			if strings.HasSuffix(object.Key, "/") ||
+				!strings.HasPrefix(object.Key, dagPrefix) {
+				return nil
+			}
+			relPath := strings.TrimPrefix(object.Key, dagPrefix)
+			observed.DagObjects[relPath] = object.ETag
+			return nil
+		})
+	if err != nil {
+		return observed, err
+	}
+
+	for _, module := range desired.Modules {
+		observedModule := ObservedModule{}
+
+		// /////////////////////////////////
+		// Artifact repository (e.g., AWS CodeArtifact)
+		// /////////////////////////////////
+		_, err = artifactRepo.DescribePackageVersion(ctx,
+			deplSpec.ArtifactRepo.Domain,
+			deplSpec.ArtifactRepo.AccountId, desired.PackageRepo,
+			module.Format, module.Namespace, module.Name, module.Version)
+		if err != nil && !isNotFound(err) {
+			return observed, err
+		}
+		observedModule.PackageVersionFound = err == nil
+
+		// /////////////////////////////////
+		// Container registry (e.g., AWS ECR)
+		// /////////////////////////////////
+		if module.ImageRepo != "" {
+			_, err = containerRegistry.DescribeImageTag(ctx, module.ImageRepo,
+				module.Version)
+			if err != nil && !isNotFound(err) {
+				return observed, err
+			}
+			observedModule.ImageTagFound = err == nil
+		}
+
+		observed.Modules = append(observed.Modules, observedModule)
+	}
+
+	// /////////////////////////////////
+	// Orchestrator (e.g., AWS MWAA)
+	// /////////////////////////////////
+	observed.Dags, err = listMatchingDags(ctx, orchestrator, desired.AirflowEnv,
+		desired.DagNamePattern)
+	if err != nil {
+		return observed, err
+	}
+
+	return observed, nil
+}
+
+// Compare the desired state with the observed one. The returned items are
+// sorted, so that the report is stable from one run to the other
+func DiffState(desired DesiredState, observed ObservedState) []PlanItem {
+	items := []PlanItem{}
+
+	// DAG files on S3
+	for dagFile, digest := range desired.DagFiles {
+		uri := fmt.Sprintf("s3://%s/%s", desired.DagBucket,
+			path.Join(desired.DagPrefix, dagFile))
+		etag, found := observed.DagObjects[dagFile]
+		etag = strings.Trim(etag, `"`)
+		switch {
+		case !found:
+			items = append(items, PlanItem{PlanAdd, "s3_object", uri,
+				"not uploaded yet"})
+		case strings.Contains(etag, "-"):
+			// Multipart upload: the ETag is not the MD5 of the content
+			// and nothing can be told about the differences
+		case etag != digest:
+			items = append(items, PlanItem{PlanChange, "s3_object", uri,
+				"content differs from the local file"})
+		}
+	}
+	for dagFile := range observed.DagObjects {
+		if _, found := desired.DagFiles[dagFile]; found {
+			continue
+		}
+		uri := fmt.Sprintf("s3://%s/%s", desired.DagBucket,
+			path.Join(desired.DagPrefix, dagFile))
+		items = append(items, PlanItem{PlanUnmanaged, "s3_object", uri,
+			"no longer in the local DAG directory"})
+	}
+
+	for idx, module := range desired.Modules {
+		observedModule := ObservedModule{}
+		if idx < len(observed.Modules) {
+			observedModule = observed.Modules[idx]
+		}
+
+		// Versioned package on the artifact repository
+		if !observedModule.PackageVersionFound {
+			name := fmt.Sprintf("%s/%s==%s", desired.PackageRepo,
+				module.Name, module.Version)
+			items = append(items, PlanItem{PlanAdd, "package_version", name,
+				"not published yet"})
+		}
+
+		// Container image on the container registry
+		if module.ImageRepo != "" && !observedModule.ImageTagFound {
+			name := fmt.Sprintf("%s:%s", module.ImageRepo, module.Version)
+			items = append(items, PlanItem{PlanAdd, "image_tag", name,
+				"not pushed yet"})
+		}
+	}
+
+	// DAGs within Airflow
+	if len(observed.Dags) == 0 {
+		items = append(items, PlanItem{PlanAdd, "airflow_dag",
+			desired.DagNamePattern, "no DAG matching the name pattern"})
+	}
+	for _, dag := range observed.Dags {
+		relPath := strings.TrimPrefix(dag.Filepath, "/")
+		if _, found := desired.DagFiles[relPath]; found {
+			continue
+		}
+		items = append(items, PlanItem{PlanUnmanaged, "airflow_dag", dag.DagId,
+			fmt.Sprintf("the %s file is not in the local DAG directory",
+				dag.Filepath)})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Resource != items[j].Resource {
+			return items[i].Resource < items[j].Resource
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	return items
+}
+
+func Plan(ctx context.Context, deplSpec utilities.SpecFile) ([]PlanItem,
+	error) {
+	_, err := Preflight(ctx, deplSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	deplSpec, err = ResolveVersions(ctx, deplSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	desired, err := BuildDesiredState(deplSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	observed, err := ObserveState(ctx, deplSpec, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffState(desired, observed), nil
+}
+
+// Terraform-style report of the plan
+func FormatPlan(items []PlanItem) string {
+	if len(items) == 0 {
+		return "No changes. The observed state matches the specification.\n"
+	}
+
+	var sb strings.Builder
+	counts := map[PlanAction]int{}
+	for _, item := range items {
+		counts[item.Action]++
+		fmt.Fprintf(&sb, "  %s %-16s %s (%s)\n", item.Action, item.Resource,
+			item.Name, item.Detail)
+	}
+	fmt.Fprintf(&sb, "\nPlan: %d to add, %d to change, %d unmanaged (left as is).\n",
+		counts[PlanAdd], counts[PlanChange], counts[PlanUnmanaged])
+
+	return sb.String()
+}
+
+// Whether the deployment would change something, the unmanaged
+// resources being left as is
+func HasChanges(items []PlanItem) bool {
+	for _, item := range items {
+		if item.Action == PlanAdd || item.Action == PlanChange {
+			return true
+		}
+	}
+	return false
+}
+
+// Prefix of the keys of the DAG objects, ending with a slash, so that
+// the objects of the sibling prefixes (e.g., dags_old/ for dags) are not
+// taken for DAG files
+func dagObjectPrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "/"
+}
+
+// Whether the error reports a resource (e.g., package version, image tag)
+// which does not exist
+func isNotFound(err error) bool {
+	return errors.Is(err, service.ErrNotFound)
+}
+
+// DAG files of the local DAG directory, with the MD5 of their content
+func localDagFiles(sourceDir string) ([]DagFile, error) {
+	dagPaths, err := listDagFiles(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	dagFiles := []DagFile{}
+	for _, dagPath := range dagPaths {
+		digest, err := fileMD5(filepath.Join(sourceDir, dagPath))
+		if err != nil {
+			return nil, err
+		}
+		dagFiles = append(dagFiles, DagFile{dagPath, digest})
+	}
+	return dagFiles, nil
+}
+
+// MD5 hex digest of a file, i.e., what S3 reports as the ETag of an object
+// uploaded in a single part
+func fileMD5(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
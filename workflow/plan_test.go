@@ -0,0 +1,78 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/plan_test.go
+//
+package workflow
+
+import (
+	"testing"
+
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+/**
+ * Check that the differences between the desired and observed states
+ * are reported as additions, changes and unmanaged resources
+ */
+func TestDiffState(t *testing.T) {
+	desired := DesiredState{
+		DagBucket: "example-bucket",
+		DagPrefix: "dags",
+		DagFiles: map[string]string{
+			"new.py":     "0cc175b9c0f1b6a831c399e269772661",
+			"same.py":    "92eb5ffee6ae2fec3ad71c777531578f",
+			"changed.py": "4a8a08f09d37b73795649038408b5f33",
+		},
+		PackageRepo: "example-repo",
+		Modules: []DesiredModule{
+			{Name: "example-pkg", Version: "0.0.1", Format: "pypi",
+				ImageRepo: "example-repo"},
+			{Name: "example-job", Version: "1.2.0", Format: "maven"},
+		},
+		DagNamePattern: "example",
+	}
+	observed := ObservedState{
+		DagObjects: map[string]string{
+			"same.py":    `"92eb5ffee6ae2fec3ad71c777531578f"`,
+			"changed.py": `"8277e0910d750195b448797616e091ad"`,
+			"old.py":     `"e1671797c52e15f763380b45e841ec32"`,
+		},
+		Modules: []ObservedModule{
+			{PackageVersionFound: true, ImageTagFound: false},
+			{PackageVersionFound: false},
+		},
+		Dags: []utilities.MwaaDagMetadata{
+			{DagId: "example_dag", Filepath: "same.py"},
+		},
+	}
+
+	items := DiffState(desired, observed)
+	expected := []PlanItem{
+		{Action: PlanAdd, Resource: "image_tag",
+			Name: "example-repo:0.0.1", Detail: "not pushed yet"},
+		{Action: PlanAdd, Resource: "package_version",
+			Name: "example-repo/example-job==1.2.0", Detail: "not published yet"},
+		{Action: PlanChange, Resource: "s3_object",
+			Name:   "s3://example-bucket/dags/changed.py",
+			Detail: "content differs from the local file"},
+		{Action: PlanAdd, Resource: "s3_object",
+			Name: "s3://example-bucket/dags/new.py", Detail: "not uploaded yet"},
+		{Action: PlanUnmanaged, Resource: "s3_object",
+			Name:   "s3://example-bucket/dags/old.py",
+			Detail: "no longer in the local DAG directory"},
+	}
+	if len(items) != len(expected) {
+		t.Fatalf(`DiffState() = %v, expected %v`, items, expected)
+	}
+	for idx, item := range items {
+		if item != expected[idx] {
+			t.Errorf(`DiffState()[%d] = %v, expected %v`,
+				idx, item, expected[idx])
+		}
+	}
+
+	// The unmanaged resources, left as is by the deployment, are no drift
+	if !HasChanges(items) || HasChanges(items[len(items)-1:]) {
+		t.Errorf(`HasChanges() = %t, %t, expected true, false`,
+			HasChanges(items), HasChanges(items[len(items)-1:]))
+	}
+}
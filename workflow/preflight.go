@@ -0,0 +1,91 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/preflight.go
+//
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+// A section of the deployment specification, with its name (as in
+// the YAML file) and its location on the cloud provider
+type specSection struct {
+	Name     string
+	Location utilities.CloudLocation
+}
+
+func specSections(deplSpec utilities.SpecFile) []specSection {
+	return []specSection{
+		{"storage_container", deplSpec.StorageContainer.CloudLocation},
+		{"artifact_repo", deplSpec.ArtifactRepo.CloudLocation},
+		{"container_repo", deplSpec.ContainerRepo.CloudLocation},
+		{"airflow", deplSpec.Airflow.CloudLocation},
+		{"compute_engine", deplSpec.ComputeEngine.CloudLocation},
+		{"kubernetes", deplSpec.Kubernetes.CloudLocation},
+	}
+}
+
+/**
+ * Check, for every section of the deployment specification, that
+ * the effective caller identity (i.e., after the IAM role, if any,
+ * has been assumed) belongs to the account configured by the `acct_id`
+ * field of that section. That prevents, for instance, the credentials
+ * of a production account from being used with a development
+ * specification. Sections without any provider are not used and are
+ * therefore skipped
+ */
+func Preflight(ctx context.Context,
+	deplSpec utilities.SpecFile) ([]SectionIdentity, error) {
+	sectionIdentities := []SectionIdentity{}
+	failures := []error{}
+
+	// The same credentials (provider, region, IAM role) are shared
+	// by several sections, for which the identity is retrieved only once
+	identities := map[service.SectionConfig]service.CallerIdentity{}
+
+	for _, section := range specSections(deplSpec) {
+		if section.Location.Provider == "" {
+			continue
+		}
+
+		cfg := sectionConfig(section.Location)
+		identityKey := cfg
+		identityKey.AccountId = ""
+
+		identity, found := identities[identityKey]
+		if !found {
+			provider, err := service.GetProvider(cfg.Provider)
+			if err != nil {
+				failures = append(failures,
+					fmt.Errorf("%s: %w", section.Name, err))
+				continue
+			}
+
+			identity, err = provider.CallerIdentity(ctx, cfg)
+			if err != nil {
+				failures = append(failures,
+					fmt.Errorf("%s: %w", section.Name, err))
+				continue
+			}
+			identities[identityKey] = identity
+			log.Printf("Caller identity for %s: %s", section.Name, identity)
+		}
+		sectionIdentities = append(sectionIdentities,
+			SectionIdentity{section.Name, identity})
+
+		if identity.Account != cfg.AccountId {
+			failures = append(failures,
+				fmt.Errorf("%s: the caller identity (%s) belongs to the %q account, whereas the specification expects the %q account",
+					section.Name, identity.Arn, identity.Account,
+					cfg.AccountId))
+		}
+	}
+
+	return sectionIdentities, errors.Join(failures...)
+}
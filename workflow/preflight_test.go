@@ -0,0 +1,276 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/preflight_test.go
+//
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+/**
+ * Check that a section with a provider which is not registered fails
+ * with an error naming that provider, and the section in the preflight
+ */
+func TestUnknownProvider(t *testing.T) {
+	cfg := service.SectionConfig{Provider: "gcp", Region: "europe-west1"}
+	expected := `the "gcp" provider is not known (known providers: aws`
+	constructors := map[string]func() error{
+		"NewObjectStorage": func() error {
+			_, err := service.NewObjectStorage(context.Background(), cfg)
+			return err
+		},
+		"NewArtifactRepository": func() error {
+			_, err := service.NewArtifactRepository(context.Background(), cfg)
+			return err
+		},
+		"NewContainerRegistry": func() error {
+			_, err := service.NewContainerRegistry(context.Background(), cfg)
+			return err
+		},
+		"NewOrchestrator": func() error {
+			_, err := service.NewOrchestrator(context.Background(), cfg)
+			return err
+		},
+		"NewComputeEngine": func() error {
+			_, err := service.NewComputeEngine(context.Background(), cfg)
+			return err
+		},
+	}
+	for name, constructor := range constructors {
+		err := constructor()
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf(`service.%s() = %v, expected %q`, name, err, expected)
+		}
+	}
+
+	deplSpec := utilities.SpecFile{}
+	deplSpec.ComputeEngine.Provider = "gcp"
+	_, err := Preflight(context.Background(), deplSpec)
+	if err == nil || !strings.Contains(err.Error(), "compute_engine: "+expected) {
+		t.Errorf(`Preflight() = %v, expected %q`, err,
+			"compute_engine: "+expected)
+	}
+}
+
+// Provider faking the caller identities: the account of the assumed role,
+// if any, or 111111111111. The calls to CallerIdentity are counted.
+// The services, when set by a test, are returned as is
+type fakeProvider struct {
+	identityCalls int
+	objectStorage service.ObjectStorage
+	artifactRepo  service.ArtifactRepository
+}
+
+func (p *fakeProvider) CallerIdentity(ctx context.Context,
+	cfg service.SectionConfig) (service.CallerIdentity, error) {
+	p.identityCalls++
+	identity := service.CallerIdentity{UserId: "AIDAEXAMPLE",
+		Account: "111111111111",
+		Arn:     "arn:aws:iam::111111111111:user/example-user"}
+	if cfg.RoleArn != "" {
+		identity.Account = strings.Split(cfg.RoleArn, ":")[4]
+		identity.Arn = cfg.RoleArn
+	}
+	return identity, nil
+}
+
+func (p *fakeProvider) ObjectStorage(ctx context.Context,
+	cfg service.SectionConfig) (service.ObjectStorage, error) {
+	if p.objectStorage != nil {
+		return p.objectStorage, nil
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (p *fakeProvider) ArtifactRepository(ctx context.Context,
+	cfg service.SectionConfig) (service.ArtifactRepository, error) {
+	if p.artifactRepo != nil {
+		return p.artifactRepo, nil
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (p *fakeProvider) ContainerRegistry(ctx context.Context,
+	cfg service.SectionConfig) (service.ContainerRegistry, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *fakeProvider) Orchestrator(ctx context.Context,
+	cfg service.SectionConfig) (service.Orchestrator, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *fakeProvider) ComputeEngine(ctx context.Context,
+	cfg service.SectionConfig) (service.ComputeEngine, error) {
+	return nil, errors.New("not implemented")
+}
+
+// Object storage holding a fixed list of objects, listed by key prefix
+// in the same way as AWS S3
+type fakeObjectStorage struct {
+	objects []service.S3Object
+}
+
+func (s *fakeObjectStorage) List(ctx context.Context, bucketName string,
+	prefix string, opts service.ListOptions,
+	fn func(service.S3Object) error) error {
+	for _, object := range s.objects {
+		if !strings.HasPrefix(object.Key, prefix) {
+			continue
+		}
+		if err := fn(object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fakeObjectStorage) Upload(ctx context.Context, bucketName string,
+	key string, filepath string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+// Artifact repository holding package versions, indexed by
+// repository/package==version, onto which the copies are recorded
+type fakeArtifactRepository struct {
+	versions map[string]service.PackageVersionDetail
+	copies   []string
+	// Error of the copy of a package version, if any
+	copyErr error
+}
+
+func fakePackageKey(repoName string, packageName string,
+	packageVersion string) string {
+	return fmt.Sprintf("%s/%s==%s", repoName, packageName, packageVersion)
+}
+
+func (r *fakeArtifactRepository) ListPackageVersions(ctx context.Context,
+	domainName string, domainOwner string, repoName string, format string,
+	namespace string, packageName string, opts service.ListOptions,
+	fn func(service.PackageVersion) error) error {
+	return errors.New("not implemented")
+}
+
+func (r *fakeArtifactRepository) DescribePackageVersion(ctx context.Context,
+	domainName string, domainOwner string, repoName string, format string,
+	namespace string, packageName string,
+	packageVersion string) (service.PackageVersionDetail, error) {
+	pkgDetail, found := r.versions[fakePackageKey(repoName, packageName,
+		packageVersion)]
+	if !found {
+		return pkgDetail, &service.Error{Service: "fake",
+			Operation: "DescribePackageVersion", Kind: service.ErrNotFound,
+			Err: errors.New(packageName + "==" + packageVersion)}
+	}
+	return pkgDetail, nil
+}
+
+func (r *fakeArtifactRepository) ListPackageVersionAssets(ctx context.Context,
+	domainName string, domainOwner string, repoName string, format string,
+	namespace string, packageName string, packageVersion string,
+	opts service.ListOptions, fn func(service.PackageAsset) error) error {
+	return errors.New("not implemented")
+}
+
+func (r *fakeArtifactRepository) ListPackageVersionDependencies(ctx context.Context,
+	domainName string, domainOwner string, repoName string, format string,
+	namespace string, packageName string, packageVersion string,
+	fn func(service.PackageDependency) error) error {
+	return errors.New("not implemented")
+}
+
+func (r *fakeArtifactRepository) CopyPackageVersion(ctx context.Context,
+	domainName string, domainOwner string, sourceRepoName string,
+	targetRepoName string, format string, namespace string,
+	packageName string, packageVersion string, revision string,
+	allowOverwrite bool) (service.PackageVersion, error) {
+	if r.copyErr != nil {
+		return service.PackageVersion{}, r.copyErr
+	}
+	r.copies = append(r.copies, fmt.Sprintf("%s@%s overwrite=%t",
+		fakePackageKey(targetRepoName, packageName, packageVersion), revision,
+		allowOverwrite))
+	pkgDetail := r.versions[fakePackageKey(sourceRepoName, packageName,
+		packageVersion)]
+	r.versions[fakePackageKey(targetRepoName, packageName,
+		packageVersion)] = pkgDetail
+	return service.PackageVersion{Package: packageName,
+		Version: packageVersion, Revision: pkgDetail.Revision,
+		Status: pkgDetail.Status}, nil
+}
+
+func (r *fakeArtifactRepository) RepositoryEndpoint(ctx context.Context,
+	domainName string, domainOwner string, repoName string,
+	format string) (service.RepositoryEndpoint, error) {
+	return service.RepositoryEndpoint{}, errors.New("not implemented")
+}
+
+// The fake provider is registered once, whatever the number of runs
+var (
+	testProvider         = &fakeProvider{}
+	registerTestProvider sync.Once
+)
+
+// Specification, every section of which is located with the fake provider,
+// in the 111111111111 account
+func fakeProviderSpec() utilities.SpecFile {
+	registerTestProvider.Do(func() {
+		service.RegisterProvider("fake", testProvider)
+	})
+	deplSpec := utilities.SpecFile{}
+	for _, location := range []*utilities.CloudLocation{
+		&deplSpec.StorageContainer.CloudLocation,
+		&deplSpec.ArtifactRepo.CloudLocation,
+		&deplSpec.ContainerRepo.CloudLocation,
+		&deplSpec.Airflow.CloudLocation,
+		&deplSpec.ComputeEngine.CloudLocation,
+		&deplSpec.Kubernetes.CloudLocation,
+	} {
+		*location = utilities.CloudLocation{Provider: "fake",
+			Region: "eu-west-1", AccountId: "111111111111"}
+	}
+	return deplSpec
+}
+
+/**
+ * Check that the preflight refuses a section, and only that one, when
+ * the caller identity belongs to another account than the one of
+ * the section, and that the identity is retrieved only once for
+ * the sections sharing the same configuration
+ */
+func TestPreflight(t *testing.T) {
+	// Same configuration for every section: a single call
+	deplSpec := fakeProviderSpec()
+	testProvider.identityCalls = 0
+	identities, err := Preflight(context.Background(), deplSpec)
+	if err != nil || len(identities) != 6 || testProvider.identityCalls != 1 {
+		t.Errorf(`Preflight() = %v, %v, with %d identity call(s), expected 6 identities and a single call`,
+			identities, err, testProvider.identityCalls)
+	}
+
+	// Another account for a section
+	deplSpec.ArtifactRepo.AccountId = "222222222222"
+	_, err = Preflight(context.Background(), deplSpec)
+	expected := `artifact_repo: the caller identity (arn:aws:iam::111111111111:user/example-user) belongs to the "111111111111" account, whereas the specification expects the "222222222222" account`
+	if err == nil || err.Error() != expected {
+		t.Errorf(`Preflight() = %v, expected %q`, err, expected)
+	}
+
+	// A role of the account of the section is assumed: the configuration
+	// differs, and the identity is retrieved for it as well
+	deplSpec.ArtifactRepo.RoleArn = "arn:aws:iam::222222222222:role/example-role"
+	testProvider.identityCalls = 0
+	identities, err = Preflight(context.Background(), deplSpec)
+	if err != nil || len(identities) != 6 || testProvider.identityCalls != 2 {
+		t.Errorf(`Preflight() = %v, %v, with %d identity call(s), expected 6 identities and two calls`,
+			identities, err, testProvider.identityCalls)
+	}
+}
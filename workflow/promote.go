@@ -0,0 +1,167 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/promote.go
+//
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+// Actions of a promotion, for every module
+const (
+	// The version is not in the target repository yet
+	PromotionCopy = "copy"
+	// The version is in the target repository, in another revision,
+	// and is overwritten (forced promotion)
+	PromotionOverwrite = "overwrite"
+	// The version is in the target repository, in the same revision
+	PromotionUpToDate = "up-to-date"
+)
+
+// Options of the `promote` command
+type PromoteOptions struct {
+	// Repository the versions are copied to (e.g., the production one),
+	// within the domain of the artifact repository
+	TargetRepo string
+	// Report what would be copied, without copying anything
+	DryRun bool
+	// Overwrite the versions already in the target repository, in
+	// another revision
+	Force bool
+}
+
+/**
+ * Promote the version of every module from the artifact repository
+ * of the specification (e.g., the development one) to a target
+ * repository (e.g., the production one), with CodeArtifact
+ * CopyPackageVersions, once signed off.
+ * The version is copied in the revision found in the source repository.
+ * A version already in the target repository, in another revision, is
+ * not overwritten unless forced: the promotion is then refused before
+ * anything is copied.
+ * Only the promotion within a same domain is supported, as
+ * CopyPackageVersions only copies within a domain: the target repository
+ * belongs to the domain of the artifact repository, which may be shared
+ * with (and the target repository administered by) another account
+ */
+func Promote(ctx context.Context, deplSpec utilities.SpecFile,
+	opts PromoteOptions) (PromoteReport, error) {
+	caDomainName := deplSpec.ArtifactRepo.Domain
+	caDomainOwner := deplSpec.ArtifactRepo.AccountId
+	caRepoName := deplSpec.ArtifactRepo.Name
+	promoteReport := PromoteReport{Domain: caDomainName,
+		SourceRepo: caRepoName, TargetRepo: opts.TargetRepo,
+		DryRun: opts.DryRun, Promotions: []PromotionRecord{}}
+
+	switch {
+	case opts.TargetRepo == "":
+		return promoteReport, errors.New("the target repository of the promotion is missing")
+	case opts.TargetRepo == caRepoName:
+		return promoteReport, fmt.Errorf("the target repository of the promotion is the %s source repository",
+			caRepoName)
+	}
+
+	// Acting with the credentials of another account than the one
+	// of the specification is refused
+	_, err := Preflight(ctx, deplSpec)
+	if err != nil {
+		return promoteReport, err
+	}
+
+	// The constraints are resolved against the source repository
+	deplSpec, err = ResolveVersions(ctx, deplSpec)
+	if err != nil {
+		return promoteReport, err
+	}
+
+	artifactRepo, err := service.NewArtifactRepository(ctx, artifactRepoConfig(deplSpec))
+	if err != nil {
+		return promoteReport, fmt.Errorf("artifact_repo: %w", err)
+	}
+
+	// /////////////////////////////////
+	// Plan - the version of every module has to be published in
+	// the source repository, and the target repository is checked
+	// for that version before anything is copied
+	// /////////////////////////////////
+	for _, module := range deplSpec.Container.Modules {
+		format := deplSpec.PackageFormat(module.Format)
+		record := PromotionRecord{Module: module.Name,
+			Namespace: module.Namespace, Version: module.Version,
+			Format: format}
+
+		source, err := artifactRepo.DescribePackageVersion(ctx, caDomainName,
+			caDomainOwner, caRepoName, format, module.Namespace, module.Name,
+			module.Version)
+		if err != nil {
+			return promoteReport, fmt.Errorf("the %s package, in version %s, cannot be found in the %s source repository: %w",
+				module.Name, module.Version, caRepoName, err)
+		}
+		if source.Status != service.PackageVersionStatusPublished {
+			return promoteReport, fmt.Errorf("the %s package, in version %s, is %s in the %s source repository, and cannot be promoted",
+				module.Name, module.Version, source.Status, caRepoName)
+		}
+		record.Revision = source.Revision
+
+		target, err := artifactRepo.DescribePackageVersion(ctx, caDomainName,
+			caDomainOwner, opts.TargetRepo, format, module.Namespace,
+			module.Name, module.Version)
+		switch {
+		case err != nil && !isNotFound(err):
+			return promoteReport, fmt.Errorf("the %s package, in version %s, cannot be looked up in the %s target repository: %w",
+				module.Name, module.Version, opts.TargetRepo, err)
+		case err != nil:
+			record.Action = PromotionCopy
+		case target.Revision == source.Revision:
+			record.Action = PromotionUpToDate
+			record.Status = target.Status
+		case !opts.Force:
+			return promoteReport, fmt.Errorf("the %s package, in version %s, is already in the %s target repository, in the %s revision instead of the %s one (the promotion may be forced)",
+				module.Name, module.Version, opts.TargetRepo, target.Revision,
+				source.Revision)
+		default:
+			record.Action = PromotionOverwrite
+		}
+		log.Printf("Promotion of the %s package, in version %s, from %s to %s: %s",
+			module.Name, module.Version, caRepoName, opts.TargetRepo,
+			record.Action)
+		promoteReport.Promotions = append(promoteReport.Promotions, record)
+	}
+
+	if opts.DryRun {
+		log.Println("Dry run: no version is copied")
+		return promoteReport, nil
+	}
+
+	// /////////////////////////////////
+	// Copy of the versions
+	// /////////////////////////////////
+	promotedAt := time.Now().UTC()
+	promoteReport.PromotedAt = &promotedAt
+	for idx := range promoteReport.Promotions {
+		record := &promoteReport.Promotions[idx]
+		if record.Action == PromotionUpToDate {
+			continue
+		}
+		pkgVersion, err := artifactRepo.CopyPackageVersion(ctx, caDomainName,
+			caDomainOwner, caRepoName, opts.TargetRepo, record.Format,
+			record.Namespace, record.Module, record.Version, record.Revision,
+			record.Action == PromotionOverwrite)
+		if err != nil {
+			return promoteReport, fmt.Errorf("the %s package, in version %s, cannot be copied from the %s repository to the %s one: %w",
+				record.Module, record.Version, caRepoName, opts.TargetRepo, err)
+		}
+		record.Status = pkgVersion.Status
+		log.Printf("The %s package, in version %s, is copied to the %s repository (%s)",
+			record.Module, record.Version, opts.TargetRepo, pkgVersion.Status)
+	}
+
+	return promoteReport, nil
+}
@@ -0,0 +1,137 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/promote_test.go
+//
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+/**
+ * Check that a promotion is refused, before any call to the cloud
+ * services, when its target is not another repository of the domain
+ */
+func TestPromote(t *testing.T) {
+	deplSpec := utilities.SpecFile{}
+	deplSpec.ArtifactRepo.Domain = "example-domain"
+	deplSpec.ArtifactRepo.Name = "example-dev"
+	tests := []struct {
+		opts     PromoteOptions
+		expected string
+	}{
+		{PromoteOptions{}, "the target repository of the promotion is missing"},
+		{PromoteOptions{TargetRepo: "example-dev"},
+			"is the example-dev source repository"},
+	}
+	for _, test := range tests {
+		promoteReport, err := Promote(context.Background(), deplSpec,
+			test.opts)
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf(`Promote(%+v) = %v, expected %q`, test.opts, err,
+				test.expected)
+		}
+		if len(promoteReport.Promotions) != 0 {
+			t.Errorf(`Promote(%+v) promoted %v`, test.opts,
+				promoteReport.Promotions)
+		}
+	}
+}
+
+/**
+ * Check the promotion of the modules with the fake provider: the dry run
+ * copies nothing, the versions missing from the target repository are
+ * copied in the revision of the source one, the versions already there
+ * are up to date, and a version in another revision is only overwritten
+ * when forced
+ */
+func TestPromoteVersions(t *testing.T) {
+	deplSpec := fakeProviderSpec()
+	deplSpec.ArtifactRepo.Domain = "example-domain"
+	deplSpec.ArtifactRepo.Name = "example-dev"
+	deplSpec.ArtifactRepo.Format = "pypi"
+	deplSpec.Container.Modules = []utilities.Module{
+		{Name: "example-pkg", Version: "0.0.1"},
+		{Name: "example-job", Version: "1.2.0"},
+	}
+	published := func(revision string) service.PackageVersionDetail {
+		return service.PackageVersionDetail{Revision: revision,
+			Status: service.PackageVersionStatusPublished}
+	}
+	artifactRepo := &fakeArtifactRepository{
+		versions: map[string]service.PackageVersionDetail{
+			"example-dev/example-pkg==0.0.1": published("REV1"),
+			"example-dev/example-job==1.2.0": published("REV2"),
+		}}
+	testProvider.artifactRepo = artifactRepo
+	defer func() { testProvider.artifactRepo = nil }()
+
+	promote := func(opts PromoteOptions) ([]string, error) {
+		artifactRepo.copies = nil
+		opts.TargetRepo = "example-prod"
+		promoteReport, err := Promote(context.Background(), deplSpec, opts)
+		actions := []string{}
+		for _, record := range promoteReport.Promotions {
+			actions = append(actions, record.Module+":"+record.Action)
+		}
+		if opts.DryRun != (promoteReport.PromotedAt == nil) && err == nil {
+			t.Errorf(`Promote(%+v) promoted at %v`, opts, promoteReport.PromotedAt)
+		}
+		return actions, err
+	}
+
+	// Dry run: nothing is copied
+	actions, err := promote(PromoteOptions{DryRun: true})
+	if err != nil || strings.Join(actions, ",") != "example-pkg:copy,example-job:copy" ||
+		len(artifactRepo.copies) != 0 {
+		t.Errorf(`Promote(dry run) = %v, %v, copied %v`, actions, err,
+			artifactRepo.copies)
+	}
+
+	// Copy, in the revision of the source repository
+	actions, err = promote(PromoteOptions{})
+	expected := "example-prod/example-pkg==0.0.1@REV1 overwrite=false," +
+		"example-prod/example-job==1.2.0@REV2 overwrite=false"
+	if err != nil || strings.Join(actions, ",") != "example-pkg:copy,example-job:copy" ||
+		strings.Join(artifactRepo.copies, ",") != expected {
+		t.Errorf(`Promote() = %v, %v, copied %v, expected %s`, actions, err,
+			artifactRepo.copies, expected)
+	}
+
+	// The versions are now up to date
+	actions, err = promote(PromoteOptions{})
+	if err != nil || strings.Join(actions, ",") != "example-pkg:up-to-date,example-job:up-to-date" ||
+		len(artifactRepo.copies) != 0 {
+		t.Errorf(`Promote(again) = %v, %v, copied %v`, actions, err,
+			artifactRepo.copies)
+	}
+
+	// Another revision in the target repository is refused, unless forced
+	artifactRepo.versions["example-prod/example-pkg==0.0.1"] = published("REV0")
+	_, err = promote(PromoteOptions{})
+	if err == nil || !strings.Contains(err.Error(), "in the REV0 revision instead of the REV1 one") ||
+		len(artifactRepo.copies) != 0 {
+		t.Errorf(`Promote(REV0) = %v, copied %v, expected a refusal`, err,
+			artifactRepo.copies)
+	}
+	actions, err = promote(PromoteOptions{Force: true})
+	expected = "example-prod/example-pkg==0.0.1@REV1 overwrite=true"
+	if err != nil || strings.Join(actions, ",") != "example-pkg:overwrite,example-job:up-to-date" ||
+		strings.Join(artifactRepo.copies, ",") != expected {
+		t.Errorf(`Promote(force) = %v, %v, copied %v, expected %s`, actions, err,
+			artifactRepo.copies, expected)
+	}
+
+	// A failed copy fails the promotion
+	delete(artifactRepo.versions, "example-prod/example-job==1.2.0")
+	artifactRepo.copyErr = errors.New("version 1.2.0: neither copied nor failed")
+	_, err = promote(PromoteOptions{})
+	if err == nil || !strings.Contains(err.Error(), "cannot be copied from the example-dev repository to the example-prod one") {
+		t.Errorf(`Promote(failed copy) = %v, expected a copy failure`, err)
+	}
+}
@@ -0,0 +1,43 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/providers.go
+//
+package workflow
+
+import (
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+// The `provider` field of every section of the deployment specification
+// selects the implementation of the corresponding service, while the
+// `region`, `acct_id` and `role_arn` fields locate that section
+func sectionConfig(location utilities.CloudLocation) service.SectionConfig {
+	return service.SectionConfig{
+		Provider:  location.Provider,
+		Region:    location.Region,
+		AccountId: location.AccountId,
+		RoleArn:   location.RoleArn,
+	}
+}
+
+func storageContainerConfig(deplSpec utilities.SpecFile) service.SectionConfig {
+	return sectionConfig(deplSpec.StorageContainer.CloudLocation)
+}
+
+func artifactRepoConfig(deplSpec utilities.SpecFile) service.SectionConfig {
+	return sectionConfig(deplSpec.ArtifactRepo.CloudLocation)
+}
+
+func containerRepoConfig(deplSpec utilities.SpecFile) service.SectionConfig {
+	return sectionConfig(deplSpec.ContainerRepo.CloudLocation)
+}
+
+// The storage container of Airflow (where the DAG files are stored)
+// belongs to the Airflow service and is therefore located with it
+func airflowConfig(deplSpec utilities.SpecFile) service.SectionConfig {
+	return sectionConfig(deplSpec.Airflow.CloudLocation)
+}
+
+func computeEngineConfig(deplSpec utilities.SpecFile) service.SectionConfig {
+	return sectionConfig(deplSpec.ComputeEngine.CloudLocation)
+}
@@ -0,0 +1,202 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/publish.go
+//
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+// Default directory of the Python distributions (wheels and sdists)
+// to be published, as built by `python -m build`
+const DefaultDistDir = "dist"
+
+// Python distributions (wheels and sdists) of a directory, sorted by
+// file name. The other files are ignored
+func ListDistributions(distDir string) ([]service.PyPIDistribution, error) {
+	entries, err := os.ReadDir(distDir)
+	if err != nil {
+		return nil, fmt.Errorf("the %s distribution directory cannot be read: %w",
+			distDir, err)
+	}
+
+	dists := []service.PyPIDistribution{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		dist, isDist, err := service.ParsePyPIDistribution(filepath.Join(distDir,
+			entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if isDist {
+			dists = append(dists, dist)
+		}
+	}
+	sort.Slice(dists, func(i, j int) bool {
+		return dists[i].Filepath < dists[j].Filepath
+	})
+	return dists, nil
+}
+
+/**
+ * Distributions of a module, among the given ones. The names are compared
+ * once normalized (e.g., induction_spark_basic is the wheel name of
+ * the induction-spark-basic module). When several versions of the module
+ * have been built, the version of the module (or the highest one matching
+ * its constraint) is selected, and returned along with its distributions
+ */
+func ModuleDistributions(module utilities.Module,
+	dists []service.PyPIDistribution) ([]service.PyPIDistribution, string,
+	error) {
+	constraint, err := utilities.ParseVersionConstraint(module.Version)
+	if err != nil {
+		return nil, "", fmt.Errorf("the version of the %s module: %w",
+			module.Name, err)
+	}
+
+	moduleName := service.NormalizePyPIName(module.Name)
+	versions := []string{}
+	for _, dist := range dists {
+		if service.NormalizePyPIName(dist.Name) == moduleName {
+			versions = append(versions, dist.Version)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, "", fmt.Errorf("no distribution of the %s module can be found",
+			module.Name)
+	}
+
+	version, err := constraint.Resolve(versions)
+	if err != nil {
+		return nil, "", fmt.Errorf("no distribution of the %s module fits its version: %w",
+			module.Name, err)
+	}
+
+	moduleDists := []service.PyPIDistribution{}
+	for _, dist := range dists {
+		if service.NormalizePyPIName(dist.Name) == moduleName &&
+			dist.Version == version {
+			moduleDists = append(moduleDists, dist)
+		}
+	}
+	return moduleDists, version, nil
+}
+
+/**
+ * Publish the Python modules (i.e., of the pypi format) onto the artifact
+ * repository, from the wheels and sdists of a local directory (e.g.,
+ * `dist/`), as `twine upload` would do after `aws codeartifact login`:
+ * the endpoint of the repository and an authorization token are retrieved,
+ * the distributions are uploaded with the PyPI upload protocol and
+ * the published version of every module is then described, to confirm it
+ */
+func Publish(ctx context.Context, deplSpec utilities.SpecFile,
+	distDir string) (PublishReport, error) {
+	publishReport := PublishReport{DistDir: distDir, Modules: []ModuleReport{}}
+
+	// Acting with the credentials of another account than the one
+	// of the specification is refused
+	_, err := Preflight(ctx, deplSpec)
+	if err != nil {
+		return publishReport, err
+	}
+
+	dists, err := ListDistributions(distDir)
+	if err != nil {
+		return publishReport, err
+	}
+
+	// /////////////////////////////////
+	// Selection of the distributions of every Python module
+	// /////////////////////////////////
+	moduleDists := [][]service.PyPIDistribution{}
+	for _, module := range deplSpec.Container.Modules {
+		format := deplSpec.PackageFormat(module.Format)
+		if format != service.PackageFormatPyPI {
+			log.Printf("The %s module is skipped, as only the modules of the %s format may be published (not %s)",
+				module.Name, service.PackageFormatPyPI, format)
+			continue
+		}
+
+		selected, version, err := ModuleDistributions(module, dists)
+		if err != nil {
+			return publishReport, err
+		}
+		moduleReport := ModuleReport{Module: module.Name,
+			Namespace: module.Namespace, Version: version, Format: format}
+		if version != module.Version {
+			moduleReport.Constraint = module.Version
+		}
+		publishReport.Modules = append(publishReport.Modules, moduleReport)
+		moduleDists = append(moduleDists, selected)
+	}
+	if len(publishReport.Modules) == 0 {
+		return publishReport, fmt.Errorf("none of the modules is of the %s format, and may be published",
+			service.PackageFormatPyPI)
+	}
+
+	// /////////////////////////////////
+	// CodeArtifact - endpoint and authorization token
+	// /////////////////////////////////
+	caDomainName := deplSpec.ArtifactRepo.Domain
+	caDomainOwner := deplSpec.ArtifactRepo.AccountId
+	caRepoName := deplSpec.ArtifactRepo.Name
+
+	artifactRepo, err := service.NewArtifactRepository(ctx, artifactRepoConfig(deplSpec))
+	if err != nil {
+		return publishReport, fmt.Errorf("artifact_repo: %w", err)
+	}
+	endpoint, err := artifactRepo.RepositoryEndpoint(ctx, caDomainName,
+		caDomainOwner, caRepoName, service.PackageFormatPyPI)
+	if err != nil {
+		return publishReport, fmt.Errorf("the endpoint of the %s CodeArtifact repository cannot be retrieved: %w",
+			caRepoName, err)
+	}
+	log.Println("Endpoint of the CodeArtifact repository:", endpoint.URL)
+	publishReport.Endpoint = endpoint.URL
+
+	// /////////////////////////////////
+	// Upload of the distributions, and confirmation of the versions
+	// /////////////////////////////////
+	for idx := range publishReport.Modules {
+		moduleReport := &publishReport.Modules[idx]
+		for _, dist := range moduleDists[idx] {
+			asset, err := service.PyPIUpload(ctx, endpoint, dist)
+			if err != nil {
+				return publishReport, fmt.Errorf("the %s distribution cannot be uploaded onto the %s CodeArtifact repository: %w",
+					dist.Filepath, caRepoName, err)
+			}
+			log.Println("Distribution uploaded:", dist.Filepath,
+				service.AssetHashSHA256+"="+asset.Hashes[service.AssetHashSHA256])
+			moduleReport.Assets = append(moduleReport.Assets, asset)
+		}
+
+		pkgDetails, err := artifactRepo.DescribePackageVersion(ctx,
+			caDomainName, caDomainOwner, caRepoName, moduleReport.Format,
+			moduleReport.Namespace, moduleReport.Module, moduleReport.Version)
+		if err != nil {
+			return publishReport, fmt.Errorf("the %s package, in version %s, cannot be found in the %s CodeArtifact repository once uploaded: %w",
+				moduleReport.Module, moduleReport.Version, caRepoName, err)
+		}
+		moduleReport.PackageVersion = &pkgDetails
+		if pkgDetails.Status != service.PackageVersionStatusPublished {
+			return publishReport, fmt.Errorf("the %s package, in version %s, is %s, not %s, once uploaded",
+				moduleReport.Module, moduleReport.Version, pkgDetails.Status,
+				service.PackageVersionStatusPublished)
+		}
+		log.Printf("The %s package is published in version %s (revision %s)",
+			moduleReport.Module, moduleReport.Version, pkgDetails.Revision)
+	}
+
+	return publishReport, nil
+}
@@ -0,0 +1,115 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/publish_test.go
+//
+package workflow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+/**
+ * Check that the distributions of a module are selected from a `dist/`
+ * directory, and uploaded with the PyPI upload protocol onto a stand-in
+ * repository
+ */
+func TestPublish(t *testing.T) {
+	distDir := t.TempDir()
+	for _, filename := range []string{"example_pkg-0.0.1-py3-none-any.whl",
+		"example_pkg-0.0.2-py3-none-any.whl", "example_pkg-0.0.2.tar.gz",
+		"other_pkg-1.0.0-py3-none-any.whl", "README.md"} {
+		err := os.WriteFile(filepath.Join(distDir, filename),
+			[]byte("content of "+filename), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dists, err := ListDistributions(distDir)
+	if err != nil {
+		t.Fatalf(`ListDistributions() = %v`, err)
+	}
+	if len(dists) != 4 {
+		t.Fatalf(`ListDistributions() = %v, expected 4 distributions`,
+			dists)
+	}
+	module := utilities.Module{Name: "example-pkg", Version: "~0.0.1"}
+	moduleDists, version, err := ModuleDistributions(module, dists)
+	if err != nil || version != "0.0.2" || len(moduleDists) != 2 {
+		t.Fatalf(`ModuleDistributions() = %v, %s, %v, expected the 2 distributions of 0.0.2`,
+			moduleDists, version, err)
+	}
+	module.Version = "0.1.0"
+	_, _, err = ModuleDistributions(module, dists)
+	if err == nil {
+		t.Errorf(`ModuleDistributions(0.1.0) = nil, expected an error`)
+	}
+
+	// Stand-in repository, checking the credentials and the form
+	uploaded := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		if username != "aws" || password != "token" {
+			http.Error(w, "invalid credentials", http.StatusForbidden)
+			return
+		}
+		file, header, err := r.FormFile("content")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		hash := sha256.New()
+		_, _ = io.Copy(hash, file)
+		if r.FormValue(":action") != "file_upload" ||
+			r.FormValue("name") != "example_pkg" ||
+			r.FormValue("version") != "0.0.2" ||
+			r.FormValue("sha256_digest") != hex.EncodeToString(hash.Sum(nil)) {
+			http.Error(w, "invalid form", http.StatusBadRequest)
+			return
+		}
+		uploaded[header.Filename] = r.FormValue("filetype") + "/" +
+			r.FormValue("pyversion")
+	}))
+	defer server.Close()
+
+	endpoint := service.RepositoryEndpoint{URL: server.URL,
+		Username: "aws", Password: "token"}
+	for _, dist := range moduleDists {
+		asset, err := service.PyPIUpload(context.Background(), endpoint, dist)
+		if err != nil {
+			t.Fatalf(`service.PyPIUpload(%s) = %v`, dist.Filepath, err)
+		}
+		if asset.Hashes[service.AssetHashSHA256] == "" {
+			t.Errorf(`service.PyPIUpload(%s) gives no SHA-256 hash`,
+				dist.Filepath)
+		}
+	}
+	expected := map[string]string{
+		"example_pkg-0.0.2-py3-none-any.whl": "bdist_wheel/py3",
+		"example_pkg-0.0.2.tar.gz":           "sdist/source",
+	}
+	if fmt.Sprint(uploaded) != fmt.Sprint(expected) {
+		t.Errorf(`uploaded distributions = %v, expected %v`, uploaded, expected)
+	}
+
+	endpoint.Password = "expired"
+	_, err = service.PyPIUpload(context.Background(), endpoint, moduleDists[0])
+	if !errors.Is(err, service.ErrAccessDenied) {
+		t.Errorf(`service.PyPIUpload() = %v, expected an access denied error`,
+			err)
+	}
+}
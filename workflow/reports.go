@@ -0,0 +1,415 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/reports.go
+//
+package workflow
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/data-engineering-helpers/dppctl/report"
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+// Reports of the commands, rendered (see report.Render()) as JSON, YAML
+// or aligned tables
+
+// Caller identity for a given section of the deployment specification
+type SectionIdentity struct {
+	Section                string `json:"section" yaml:"section"`
+	service.CallerIdentity `yaml:",inline"`
+}
+
+// Results for a given module (package) of the deployment specification
+type ModuleReport struct {
+	Module          string                        `json:"module" yaml:"module"`
+	Namespace       string                        `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Version         string                        `json:"version" yaml:"version"`
+	Constraint      string                        `json:"constraint,omitempty" yaml:"constraint,omitempty"`
+	Format          string                        `json:"format" yaml:"format"`
+	PackageVersions []service.PackageVersion      `json:"package_versions,omitempty" yaml:"package_versions,omitempty"`
+	PackageVersion  *service.PackageVersionDetail `json:"package_version,omitempty" yaml:"package_version,omitempty"`
+	Assets          []service.PackageAsset        `json:"assets,omitempty" yaml:"assets,omitempty"`
+	AssetsSkipped   bool                          `json:"assets_skipped,omitempty" yaml:"assets_skipped,omitempty"`
+	Requirements    []RequirementReport           `json:"requirements,omitempty" yaml:"requirements,omitempty"`
+	ImageRepo       string                        `json:"image_repo,omitempty" yaml:"image_repo,omitempty"`
+	Image           *service.ImageDetail          `json:"image,omitempty" yaml:"image,omitempty"`
+}
+
+// Requirement declared by the published version of a module, compared
+// with the version of the dependency in the specification, if any
+type RequirementReport struct {
+	Package   string `json:"package" yaml:"package"`
+	Specifier string `json:"specifier" yaml:"specifier"`
+	Specified string `json:"specified,omitempty" yaml:"specified,omitempty"`
+	Conflict  bool   `json:"conflict" yaml:"conflict"`
+}
+
+// DAG file of the local DAG directory, with the MD5 hex digest of its
+// content (i.e., the ETag of the object, once uploaded in a single part)
+type DagFile struct {
+	Path string `json:"path" yaml:"path"`
+	MD5  string `json:"md5" yaml:"md5"`
+}
+
+// Result for a given dependency of the deployment specification
+type DependencyReport struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+	Format  string `json:"format" yaml:"format"`
+	// Status of the version within the artifact repository, if found
+	Status string `json:"status" yaml:"status"`
+}
+
+// Report of the `check` command
+type CheckReport struct {
+	Identities   []SectionIdentity           `json:"identities" yaml:"identities"`
+	Bucket       string                      `json:"bucket" yaml:"bucket"`
+	Prefix       string                      `json:"prefix" yaml:"prefix"`
+	Objects      []service.S3Object          `json:"objects" yaml:"objects"`
+	Modules      []ModuleReport              `json:"modules" yaml:"modules"`
+	Dependencies []DependencyReport          `json:"dependencies" yaml:"dependencies"`
+	Images       []service.ImageDetail       `json:"images" yaml:"images"`
+	DagSourceDir string                      `json:"dag_source_dir" yaml:"dag_source_dir"`
+	DagFiles     []DagFile                   `json:"dag_files" yaml:"dag_files"`
+	DagBucket    string                      `json:"dag_bucket" yaml:"dag_bucket"`
+	DagPrefix    string                      `json:"dag_prefix" yaml:"dag_prefix"`
+	DagObjects   []service.S3Object          `json:"dag_objects" yaml:"dag_objects"`
+	Dags         []utilities.MwaaDagMetadata `json:"dags" yaml:"dags"`
+	Cluster      *service.ClusterDetail      `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	Warnings     []string                    `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	Failures     []string                    `json:"failures" yaml:"failures"`
+}
+
+// Summary of the `plan` command, in the same way as Terraform reports it
+type PlanSummary struct {
+	Add       int `json:"add" yaml:"add"`
+	Change    int `json:"change" yaml:"change"`
+	Unmanaged int `json:"unmanaged" yaml:"unmanaged"`
+}
+
+// Report of the `plan` command
+type PlanReport struct {
+	Items   []PlanItem  `json:"items" yaml:"items"`
+	Summary PlanSummary `json:"summary" yaml:"summary"`
+}
+
+// Object uploaded onto the storage container
+type UploadedObject struct {
+	Source string `json:"source" yaml:"source"`
+	URI    string `json:"uri" yaml:"uri"`
+	ETag   string `json:"etag" yaml:"etag"`
+}
+
+// Report of the `deploy` command
+type DeployReport struct {
+	// Differences between the lock and the live state, in locked mode
+	LockDifferences []string                    `json:"lock_differences,omitempty" yaml:"lock_differences,omitempty"`
+	Modules         []ModuleReport              `json:"modules" yaml:"modules"`
+	Uploaded        []UploadedObject            `json:"uploaded" yaml:"uploaded"`
+	Dags            []utilities.MwaaDagMetadata `json:"dags" yaml:"dags"`
+}
+
+// Report of the `publish` command
+type PublishReport struct {
+	DistDir  string         `json:"dist_dir" yaml:"dist_dir"`
+	Endpoint string         `json:"endpoint" yaml:"endpoint"`
+	Modules  []ModuleReport `json:"modules" yaml:"modules"`
+}
+
+// Promotion of the version of a module to the target repository
+type PromotionRecord struct {
+	Module    string `json:"module" yaml:"module"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Version   string `json:"version" yaml:"version"`
+	Format    string `json:"format" yaml:"format"`
+	// Revision of the version in the source repository, which is copied
+	Revision string `json:"revision" yaml:"revision"`
+	// copy, overwrite or up-to-date (see PromotionCopy)
+	Action string `json:"action" yaml:"action"`
+	// Status of the version in the target repository, once copied
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// Report of the `promote` command. The time of the promotion is absent
+// from a dry run
+type PromoteReport struct {
+	Domain     string            `json:"domain" yaml:"domain"`
+	SourceRepo string            `json:"source_repo" yaml:"source_repo"`
+	TargetRepo string            `json:"target_repo" yaml:"target_repo"`
+	DryRun     bool              `json:"dry_run" yaml:"dry_run"`
+	PromotedAt *time.Time        `json:"promoted_at,omitempty" yaml:"promoted_at,omitempty"`
+	Promotions []PromotionRecord `json:"promotions" yaml:"promotions"`
+}
+
+// Report of the modules of a deployment specification
+func newModuleReports(deplSpec utilities.SpecFile) []ModuleReport {
+	moduleReports := make([]ModuleReport, len(deplSpec.Container.Modules))
+	for idx, module := range deplSpec.Container.Modules {
+		moduleReports[idx] = ModuleReport{Module: module.Name,
+			Namespace: module.Namespace,
+			Version:   module.Version, Constraint: module.Constraint,
+			Format:    deplSpec.PackageFormat(module.Format),
+			ImageRepo: module.Image}
+	}
+	return moduleReports
+}
+
+// Report of the `validate` command
+type ValidationReport struct {
+	Files  []string                    `json:"files" yaml:"files"`
+	Valid  bool                        `json:"valid" yaml:"valid"`
+	Errors []utilities.ValidationError `json:"errors" yaml:"errors"`
+}
+
+func NewPlanReport(items []PlanItem) PlanReport {
+	planReport := PlanReport{Items: items}
+	for _, item := range items {
+		switch item.Action {
+		case PlanAdd:
+			planReport.Summary.Add++
+		case PlanChange:
+			planReport.Summary.Change++
+		case PlanUnmanaged:
+			planReport.Summary.Unmanaged++
+		}
+	}
+	return planReport
+}
+
+func (r CheckReport) RenderTable(w io.Writer) error {
+	tw := report.NewTableWriter(w)
+
+	fmt.Fprintln(tw, "SECTION\tACCOUNT\tARN")
+	for _, identity := range r.Identities {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", identity.Section, identity.Account,
+			identity.Arn)
+	}
+
+	fmt.Fprintf(tw, "\nOBJECT (s3://%s/%s)\tSIZE\tLAST MODIFIED\tETAG\n",
+		r.Bucket, r.Prefix)
+	for _, object := range r.Objects {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", object.Key, object.Size,
+			report.FormatTime(object.LastModified), object.ETag)
+	}
+
+	fmt.Fprintln(tw, "\nMODULE\tPACKAGE VERSION\tSTATUS\tREVISION")
+	for _, moduleReport := range r.Modules {
+		for _, pkgVersion := range moduleReport.PackageVersions {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", moduleReport.Module,
+				pkgVersion.Version, pkgVersion.Status, pkgVersion.Revision)
+		}
+	}
+
+	fmt.Fprintln(tw)
+	renderModules(tw, r.Modules)
+
+	fmt.Fprintln(tw)
+	renderAssets(tw, r.Modules)
+
+	fmt.Fprintln(tw, "\nMODULE\tREQUIRES\tSPECIFIER\tSPECIFIED\tCONFLICT")
+	for _, moduleReport := range r.Modules {
+		for _, requirement := range moduleReport.Requirements {
+			specified := requirement.Specified
+			if specified == "" {
+				specified = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\n", moduleReport.Module,
+				requirement.Package, requirement.Specifier, specified,
+				requirement.Conflict)
+		}
+	}
+
+	fmt.Fprintln(tw, "\nDEPENDENCY\tVERSION\tFORMAT\tSTATUS")
+	for _, dependency := range r.Dependencies {
+		status := dependency.Status
+		if status == "" {
+			status = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", dependency.Name,
+			dependency.Version, dependency.Format, status)
+	}
+
+	fmt.Fprintln(tw, "\nIMAGE TAGS\tDIGEST\tPUSHED AT\tSIZE\tSCAN STATUS")
+	for _, image := range r.Images {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", strings.Join(image.Tags, ","),
+			image.Digest, report.FormatTime(image.PushedAt),
+			image.SizeInBytes, image.ScanStatus)
+	}
+
+	fmt.Fprintf(tw, "\nDAG FILE (%s)\tMD5\n", r.DagSourceDir)
+	for _, dagFile := range r.DagFiles {
+		fmt.Fprintf(tw, "%s\t%s\n", dagFile.Path, dagFile.MD5)
+	}
+
+	fmt.Fprintf(tw, "\nDAG OBJECT (s3://%s/%s)\tSIZE\tLAST MODIFIED\tETAG\n",
+		r.DagBucket, r.DagPrefix)
+	for _, object := range r.DagObjects {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", object.Key, object.Size,
+			report.FormatTime(object.LastModified), object.ETag)
+	}
+
+	fmt.Fprintln(tw, "\nDAG\tFILEPATH\tOWNER\tPAUSED")
+	for _, dag := range r.Dags {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", dag.DagId, dag.Filepath, dag.Owner,
+			dag.Paused)
+	}
+
+	if r.Cluster != nil {
+		fmt.Fprintln(tw, "\nCLUSTER\tID\tRELEASE\tSTATE")
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Cluster.Name, r.Cluster.Id,
+			r.Cluster.ReleaseLabel, r.Cluster.State)
+	}
+
+	if len(r.Warnings) > 0 {
+		fmt.Fprintln(tw, "\nWARNING")
+		for _, warning := range r.Warnings {
+			fmt.Fprintln(tw, warning)
+		}
+	}
+
+	if len(r.Failures) > 0 {
+		fmt.Fprintln(tw, "\nFAILURE")
+		for _, failure := range r.Failures {
+			fmt.Fprintln(tw, failure)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func (r PlanReport) RenderTable(w io.Writer) error {
+	_, err := io.WriteString(w, FormatPlan(r.Items))
+	return err
+}
+
+func (r DeployReport) RenderTable(w io.Writer) error {
+	tw := report.NewTableWriter(w)
+
+	if len(r.LockDifferences) > 0 {
+		fmt.Fprintln(tw, "LOCK DIFFERENCE")
+		for _, diff := range r.LockDifferences {
+			fmt.Fprintln(tw, diff)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	renderModules(tw, r.Modules)
+
+	fmt.Fprintln(tw, "\nUPLOADED\tTO\tETAG")
+	for _, object := range r.Uploaded {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", object.Source, object.URI, object.ETag)
+	}
+
+	fmt.Fprintln(tw, "\nDAG\tFILEPATH\tOWNER\tPAUSED")
+	for _, dag := range r.Dags {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", dag.DagId, dag.Filepath, dag.Owner,
+			dag.Paused)
+	}
+
+	return tw.Flush()
+}
+
+func (r PublishReport) RenderTable(w io.Writer) error {
+	tw := report.NewTableWriter(w)
+
+	fmt.Fprintf(tw, "UPLOADED (%s)\tTO\n", r.DistDir)
+	for _, moduleReport := range r.Modules {
+		for _, asset := range moduleReport.Assets {
+			fmt.Fprintf(tw, "%s\t%s\n", asset.Name, r.Endpoint)
+		}
+	}
+
+	fmt.Fprintln(tw)
+	renderModules(tw, r.Modules)
+
+	fmt.Fprintln(tw)
+	renderAssets(tw, r.Modules)
+
+	return tw.Flush()
+}
+
+func (r PromoteReport) RenderTable(w io.Writer) error {
+	tw := report.NewTableWriter(w)
+
+	title := fmt.Sprintf("PROMOTION (%s: %s -> %s)", r.Domain, r.SourceRepo,
+		r.TargetRepo)
+	if r.DryRun {
+		title += " - DRY RUN"
+	} else if r.PromotedAt != nil {
+		title += " - " + report.FormatTime(*r.PromotedAt)
+	}
+	fmt.Fprintf(tw, "%s\tVERSION\tREVISION\tACTION\tSTATUS\n", title)
+	for _, record := range r.Promotions {
+		module, status := record.Module, record.Status
+		if record.Namespace != "" {
+			module = record.Namespace + "/" + module
+		}
+		if status == "" {
+			status = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", module, record.Version,
+			record.Revision, record.Action, status)
+	}
+
+	return tw.Flush()
+}
+
+// One line per module, with the package version and the image found
+// for it ("-" when not found, or when the module ships no image)
+func renderModules(w io.Writer, moduleReports []ModuleReport) {
+	fmt.Fprintln(w, "MODULE\tVERSION\tFORMAT\tPACKAGE STATUS\tIMAGE\tDIGEST")
+	for _, moduleReport := range moduleReports {
+		status, image, digest := "-", "-", "-"
+		module, version := moduleReport.Module, moduleReport.Version
+		if moduleReport.Namespace != "" {
+			module = moduleReport.Namespace + "/" + module
+		}
+		if moduleReport.Constraint != "" {
+			version += " (" + moduleReport.Constraint + ")"
+		}
+		if moduleReport.PackageVersion != nil {
+			status = moduleReport.PackageVersion.Status
+		}
+		if moduleReport.ImageRepo != "" {
+			image = moduleReport.ImageRepo + ":" + moduleReport.Version
+		}
+		if moduleReport.Image != nil {
+			digest = moduleReport.Image.Digest
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", module, version,
+			moduleReport.Format, status, image, digest)
+	}
+}
+
+// One line per asset (file) of the package version of every module
+func renderAssets(w io.Writer, moduleReports []ModuleReport) {
+	fmt.Fprintln(w, "MODULE\tASSET\tSIZE\tSHA-256")
+	for _, moduleReport := range moduleReports {
+		for _, asset := range moduleReport.Assets {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", moduleReport.Module,
+				asset.Name, asset.Size, asset.Hashes[service.AssetHashSHA256])
+		}
+	}
+}
+
+// The errors are listed in the same way as the compilers do, for
+// the editors to jump to them
+func (r ValidationReport) RenderTable(w io.Writer) error {
+	if r.Valid {
+		_, err := fmt.Fprintf(w, "The %s specification is valid.\n",
+			strings.Join(r.Files, " + "))
+		return err
+	}
+
+	for _, validationError := range r.Errors {
+		_, err := fmt.Fprintln(w, validationError.Error())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
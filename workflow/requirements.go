@@ -0,0 +1,86 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/requirements.go
+//
+package workflow
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+// Dependency of the specification (`container.dependencies`) a requirement
+// of a module is about, if any. The names of the Python packages are
+// compared once normalized (e.g., delta_spark is delta-spark)
+func specDependency(deplSpec utilities.SpecFile, format string,
+	requirement service.PackageDependency) (utilities.Dependency, bool) {
+	for _, dependency := range deplSpec.Container.Dependencies {
+		if deplSpec.PackageFormat(dependency.Format) != format {
+			continue
+		}
+		if format == service.PackageFormatPyPI {
+			if service.NormalizePyPIName(dependency.Name) ==
+				service.NormalizePyPIName(requirement.Package) {
+				return dependency, true
+			}
+			continue
+		}
+		if dependency.Name == requirement.Package &&
+			dependency.Namespace == requirement.Namespace {
+			return dependency, true
+		}
+	}
+	return utilities.Dependency{}, false
+}
+
+/**
+ * Compare the requirements declared by the published version of a module
+ * (e.g., `pyspark==3.3.0` in the metadata of its wheel) with the versions
+ * of the dependencies of the specification (e.g., pyspark 3.3.0 and
+ * delta-spark 2.1.1). The requirements on other packages are reported
+ * as is. Only the specifiers of the Python packages (PEP 440) are
+ * compared. The conflicts are returned as messages, giving the specifier
+ * which caused them
+ */
+func CheckRequirements(deplSpec utilities.SpecFile, module utilities.Module,
+	requirements []service.PackageDependency) ([]RequirementReport, []string) {
+	format := deplSpec.PackageFormat(module.Format)
+	requirementReports := []RequirementReport{}
+	conflicts := []string{}
+	for _, requirement := range requirements {
+		requirementReport := RequirementReport{Package: requirement.Package,
+			Specifier: requirement.VersionRequirement}
+		dependency, found := specDependency(deplSpec, format, requirement)
+		if !found || format != service.PackageFormatPyPI {
+			requirementReports = append(requirementReports, requirementReport)
+			continue
+		}
+		requirementReport.Specified = dependency.Version
+
+		constraint, err := utilities.ParseRequirementSpecifier(requirement.VersionRequirement)
+		if err != nil {
+			log.Printf("The %s requirement of the %s module cannot be compared with the specification: %v",
+				requirement.Package, module.Name, err)
+			requirementReports = append(requirementReports, requirementReport)
+			continue
+		}
+		version, err := utilities.ParseSemVer(dependency.Version)
+		if err != nil {
+			log.Printf("The %s requirement of the %s module cannot be compared with the specification: %v",
+				requirement.Package, module.Name, err)
+			requirementReports = append(requirementReports, requirementReport)
+			continue
+		}
+
+		if !constraint.Matches(version) {
+			requirementReport.Conflict = true
+			conflicts = append(conflicts, fmt.Sprintf("the %s module, in version %s, requires %s %s, whereas the %s version is specified (container.dependencies)",
+				module.Name, module.Version, requirement.Package,
+				requirement.VersionRequirement, dependency.Version))
+		}
+		requirementReports = append(requirementReports, requirementReport)
+	}
+	return requirementReports, conflicts
+}
@@ -0,0 +1,44 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/requirements_test.go
+//
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+/**
+ * Check that the requirements declared by a module are compared with
+ * the dependencies of the specification
+ */
+func TestCheckRequirements(t *testing.T) {
+	deplSpec := utilities.SpecFile{}
+	deplSpec.ArtifactRepo.Format = "pypi"
+	deplSpec.Container.Dependencies = []utilities.Dependency{
+		{Name: "pyspark", Version: "3.3.0"},
+		{Name: "delta-spark", Version: "2.1.1"},
+	}
+	module := utilities.Module{Name: "example-pkg", Version: "0.0.1"}
+	requirements := []service.PackageDependency{
+		{Package: "pyspark", VersionRequirement: "==3.3.0"},
+		{Package: "delta_spark", VersionRequirement: ">=2.4,<2.5"},
+		{Package: "numpy", VersionRequirement: ">=1.22"},
+	}
+	requirementReports, conflicts := CheckRequirements(deplSpec,
+		module, requirements)
+	expected := []string{
+		"the example-pkg module, in version 0.0.1, requires delta_spark >=2.4,<2.5, whereas the 2.1.1 version is specified (container.dependencies)",
+	}
+	if strings.Join(conflicts, "\n") != strings.Join(expected, "\n") {
+		t.Errorf(`CheckRequirements() = %q, expected %q`, conflicts,
+			expected)
+	}
+	if len(requirementReports) != 3 || requirementReports[0].Conflict ||
+		!requirementReports[1].Conflict || requirementReports[2].Specified != "" {
+		t.Errorf(`CheckRequirements() = %v`, requirementReports)
+	}
+}
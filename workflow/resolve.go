@@ -0,0 +1,82 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/resolve.go
+//
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+/**
+ * Resolve the version constraints of the modules (e.g., `~0.0.1`,
+ * `>=0.1,<0.2` or `latest`, see utilities.VersionConstraint) against
+ * the artifact repository: the highest published version matching
+ * the constraint is selected, the unlisted and archived versions being
+ * skipped. The exact versions are kept, without their operator (e.g.,
+ * 1.2.3 for `=1.2.3`), without calling the artifact repository.
+ * The returned specification has the resolved versions, to be used
+ * everywhere in the run (e.g., as the tags of the images), the constraints
+ * being kept in the Constraint field of the modules
+ */
+func ResolveVersions(ctx context.Context,
+	deplSpec utilities.SpecFile) (utilities.SpecFile, error) {
+	// The modules of the given specification are left untouched
+	modules := make([]utilities.Module, len(deplSpec.Container.Modules))
+	copy(modules, deplSpec.Container.Modules)
+	deplSpec.Container.Modules = modules
+
+	var artifactRepo service.ArtifactRepository
+	for idx, module := range modules {
+		constraint, err := utilities.ParseVersionConstraint(module.Version)
+		if err != nil {
+			return deplSpec, fmt.Errorf("the version of the %s module: %w",
+				module.Name, err)
+		}
+		if constraint.IsExact() {
+			if constraint.ExactVersion() != module.Version {
+				modules[idx].Constraint = module.Version
+				modules[idx].Version = constraint.ExactVersion()
+			}
+			continue
+		}
+
+		if artifactRepo == nil {
+			artifactRepo, err = service.NewArtifactRepository(ctx, artifactRepoConfig(deplSpec))
+			if err != nil {
+				return deplSpec, fmt.Errorf("artifact_repo: %w", err)
+			}
+		}
+
+		versions := []string{}
+		err = artifactRepo.ListPackageVersions(ctx,
+			deplSpec.ArtifactRepo.Domain, deplSpec.ArtifactRepo.AccountId,
+			deplSpec.ArtifactRepo.Name, deplSpec.PackageFormat(module.Format),
+			module.Namespace, module.Name,
+			service.ListOptions{Status: service.PackageVersionStatusPublished},
+			func(pkgVersion service.PackageVersion) error {
+				versions = append(versions, pkgVersion.Version)
+				return nil
+			})
+		if err != nil {
+			return deplSpec, fmt.Errorf("the published versions of the %s module cannot be retrieved from the %s repository: %w",
+				module.Name, deplSpec.ArtifactRepo.Name, err)
+		}
+
+		version, err := constraint.Resolve(versions)
+		if err != nil {
+			return deplSpec, fmt.Errorf("the version of the %s module cannot be resolved: %w",
+				module.Name, err)
+		}
+		log.Printf("The %s constraint of the %s module is resolved to the %s version",
+			module.Version, module.Name, version)
+		modules[idx].Constraint = module.Version
+		modules[idx].Version = version
+	}
+
+	return deplSpec, nil
+}
@@ -0,0 +1,41 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/resolve_test.go
+//
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+/**
+ * Check that the exact versions are kept, without their operator, and
+ * without calling the artifact repository (which the fake provider
+ * does not implement)
+ */
+func TestResolveExactVersions(t *testing.T) {
+	deplSpec := fakeProviderSpec()
+	deplSpec.Container.Modules = []utilities.Module{
+		{Name: "example-pkg", Version: "=1.2.3"},
+		{Name: "example-job", Version: "1.0-SNAPSHOT"},
+	}
+	resolved, err := ResolveVersions(context.Background(), deplSpec)
+	if err != nil {
+		t.Fatalf(`ResolveVersions() = %v`, err)
+	}
+	expected := []utilities.Module{
+		{Name: "example-pkg", Version: "1.2.3", Constraint: "=1.2.3"},
+		{Name: "example-job", Version: "1.0-SNAPSHOT"},
+	}
+	for idx, module := range resolved.Container.Modules {
+		if module != expected[idx] {
+			t.Errorf(`ResolveVersions() = %+v, expected %+v`, module,
+				expected[idx])
+		}
+	}
+	if deplSpec.Container.Modules[0].Version != "=1.2.3" {
+		t.Errorf(`ResolveVersions() changed the given specification`)
+	}
+}
@@ -0,0 +1,62 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/tasks.go
+//
+package workflow
+
+import (
+	"context"
+	"sync"
+)
+
+// Default number of tasks run at the same time (see the `-p` command-line
+// option)
+const DefaultParallelism = 4
+
+// Independent unit of work of a command (e.g., one of the checks), about
+// a given section of the deployment specification
+type task struct {
+	Section string
+	Run     func(ctx context.Context) error
+}
+
+/**
+ * Run the tasks with a pool of workers, so that at most `parallelism`
+ * of them are running at the same time (DefaultParallelism when it is
+ * not positive). The errors are returned in the order of the tasks,
+ * whatever the order in which they completed.
+ * Once the context has been cancelled (e.g., on Ctrl-C), the tasks
+ * not started yet are skipped and get the error of the context
+ */
+func runTasks(ctx context.Context, parallelism int, tasks []task) []error {
+	errs := make([]error, len(tasks))
+	if parallelism < 1 {
+		parallelism = DefaultParallelism
+	}
+	if parallelism > len(tasks) {
+		parallelism = len(tasks)
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < parallelism; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indices {
+				if err := ctx.Err(); err != nil {
+					errs[idx] = err
+					continue
+				}
+				errs[idx] = tasks[idx].Run(ctx)
+			}
+		}()
+	}
+
+	for idx := range tasks {
+		indices <- idx
+	}
+	close(indices)
+	wg.Wait()
+
+	return errs
+}
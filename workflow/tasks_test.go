@@ -0,0 +1,126 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/tasks_test.go
+//
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/**
+ * Check that the errors are returned in the order of the tasks, whatever
+ * the order in which they completed (the first tasks being the slowest)
+ */
+func TestRunTasksOrder(t *testing.T) {
+	tasks := []task{}
+	for idx := 0; idx < 8; idx++ {
+		idx := idx
+		tasks = append(tasks, task{"example", func(ctx context.Context) error {
+			time.Sleep(time.Duration(8-idx) * 5 * time.Millisecond)
+			if idx%2 == 1 {
+				return fmt.Errorf("task %d", idx)
+			}
+			return nil
+		}})
+	}
+
+	errs := runTasks(context.Background(), 4, tasks)
+	if len(errs) != len(tasks) {
+		t.Fatalf(`runTasks() = %v, expected %d errors`, errs, len(tasks))
+	}
+	for idx, err := range errs {
+		expected := ""
+		if idx%2 == 1 {
+			expected = fmt.Sprintf("task %d", idx)
+		}
+		if (err == nil) != (expected == "") ||
+			(err != nil && err.Error() != expected) {
+			t.Errorf(`runTasks()[%d] = %v, expected %q`, idx, err, expected)
+		}
+	}
+}
+
+// Highest number of tasks running at the same time, out of 12 tasks
+func maxRunningTasks(parallelism int) int32 {
+	var running, maxRunning int32
+	tasks := []task{}
+	for idx := 0; idx < 12; idx++ {
+		tasks = append(tasks, task{"example", func(ctx context.Context) error {
+			current := atomic.AddInt32(&running, 1)
+			for {
+				highest := atomic.LoadInt32(&maxRunning)
+				if current <= highest ||
+					atomic.CompareAndSwapInt32(&maxRunning, highest, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		}})
+	}
+	runTasks(context.Background(), parallelism, tasks)
+	return atomic.LoadInt32(&maxRunning)
+}
+
+/**
+ * Check that no more than `parallelism` tasks run at the same time,
+ * DefaultParallelism being used when it is not positive
+ */
+func TestRunTasksParallelism(t *testing.T) {
+	tests := []struct {
+		parallelism int
+		expected    int32
+	}{
+		{1, 1},
+		{3, 3},
+		{20, 12},
+		{0, DefaultParallelism},
+		{-1, DefaultParallelism},
+	}
+	for _, test := range tests {
+		maxRunning := maxRunningTasks(test.parallelism)
+		if maxRunning != test.expected {
+			t.Errorf(`runTasks(%d) ran %d tasks at the same time, expected %d`,
+				test.parallelism, maxRunning, test.expected)
+		}
+	}
+}
+
+/**
+ * Check that, once the context has been cancelled, the tasks not
+ * started yet are not run, and get the error of the context
+ */
+func TestRunTasksCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs int32
+	tasks := []task{{"example", func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		cancel()
+		return nil
+	}}}
+	for idx := 0; idx < 5; idx++ {
+		tasks = append(tasks, task{"example", func(ctx context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		}})
+	}
+
+	errs := runTasks(ctx, 1, tasks)
+	if runs != 1 {
+		t.Errorf(`runTasks() ran %d tasks once cancelled, expected 1`, runs)
+	}
+	for idx, err := range errs {
+		if (idx == 0) != (err == nil) ||
+			(idx > 0 && !errors.Is(err, context.Canceled)) {
+			t.Errorf(`runTasks()[%d] = %v`, idx, err)
+		}
+	}
+}
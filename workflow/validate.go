@@ -0,0 +1,40 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/validate.go
+//
+package workflow
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+/**
+ * Validate the deployment specification, made of one or several layered
+ * files, without calling any cloud service (see utilities.ValidateSpec()
+ * for the rules). The errors are recorded in the report, with their
+ * position within the files
+ */
+func Validate(specFilepaths ...string) (ValidationReport, error) {
+	validationReport := ValidationReport{Files: specFilepaths,
+		Errors: []utilities.ValidationError{}}
+
+	validationErrors, err := utilities.ValidateSpecFile(specFilepaths...)
+	if err != nil {
+		return validationReport, err
+	}
+	validationReport.Errors = append(validationReport.Errors,
+		validationErrors...)
+	validationReport.Valid = len(validationErrors) == 0
+
+	if !validationReport.Valid {
+		return validationReport, fmt.Errorf("%d validation error(s) in the %s specification",
+			len(validationErrors), strings.Join(specFilepaths, " + "))
+	}
+
+	log.Println("The specification is valid:",
+		strings.Join(specFilepaths, " + "))
+	return validationReport, nil
+}
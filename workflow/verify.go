@@ -0,0 +1,92 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/verify.go
+//
+package workflow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+)
+
+// Local build artifacts, against which the assets of the packages
+// are verified
+type localDist struct {
+	Dir    string
+	Hashes map[string]string
+}
+
+// SHA-256 hashes of the files of a local directory (e.g., `dist/`, as built
+// by the CI), indexed by file name. The sub-directories are ignored
+func LocalAssetHashes(distDir string) (map[string]string, error) {
+	entries, err := os.ReadDir(distDir)
+	if err != nil {
+		return nil, fmt.Errorf("the %s distribution directory cannot be read: %w",
+			distDir, err)
+	}
+
+	hashes := map[string]string{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		hash, err := fileSHA256(filepath.Join(distDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		hashes[entry.Name()] = hash
+	}
+	return hashes, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", fmt.Errorf("the %s file cannot be read: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+/**
+ * Verify the assets of a package version against the local build
+ * artifacts (file name -> SHA-256), so as to prove that the published
+ * files are the ones built by the CI, and not a re-upload. Only
+ * the assets found locally are compared (e.g., the POM files of a Maven
+ * artifact may not be in the build directory). A package none of
+ * the assets of which is found (e.g., a module built by another
+ * pipeline) is not verified, which is returned as false. The differences
+ * are returned as messages
+ */
+func VerifyAssets(assets []service.PackageAsset,
+	localHashes map[string]string, distDir string) (bool, []string) {
+	diffs := []string{}
+	verified := 0
+	for _, asset := range assets {
+		localHash, found := localHashes[asset.Name]
+		if !found {
+			continue
+		}
+		verified++
+		publishedHash := asset.Hashes[service.AssetHashSHA256]
+		if publishedHash != localHash {
+			diffs = append(diffs, fmt.Sprintf("the %s asset has the %s SHA-256, whereas the one built into %s has the %s SHA-256",
+				asset.Name, publishedHash, distDir, localHash))
+		}
+	}
+	sort.Strings(diffs)
+
+	return verified > 0, diffs
+}
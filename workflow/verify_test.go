@@ -0,0 +1,64 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/verify_test.go
+//
+package workflow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+)
+
+/**
+ * Check that the assets of a package version are verified against
+ * the files built into a local directory
+ */
+func TestVerifyAssets(t *testing.T) {
+	distDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(distDir,
+		"example_pkg-0.0.1-py3-none-any.whl"), []byte("wheel"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	localHashes, err := LocalAssetHashes(distDir)
+	if err != nil {
+		t.Fatalf(`LocalAssetHashes() = %v`, err)
+	}
+	wheelHash := sha256.Sum256([]byte("wheel"))
+
+	asset := func(name string, hash string) service.PackageAsset {
+		return service.PackageAsset{Name: name,
+			Hashes: map[string]string{service.AssetHashSHA256: hash}}
+	}
+	tests := []struct {
+		assets   []service.PackageAsset
+		verified bool
+		expected string
+	}{
+		{[]service.PackageAsset{
+			asset("example_pkg-0.0.1-py3-none-any.whl",
+				hex.EncodeToString(wheelHash[:])),
+			asset("example_pkg-0.0.1.tar.gz", "aaa"),
+		}, true, ""},
+		{[]service.PackageAsset{
+			asset("example_pkg-0.0.1-py3-none-any.whl", "bbb"),
+		}, true, "the example_pkg-0.0.1-py3-none-any.whl asset has the bbb SHA-256, whereas the one built into " +
+			distDir + " has the " + hex.EncodeToString(wheelHash[:]) +
+			" SHA-256"},
+		{[]service.PackageAsset{
+			asset("example_pkg-0.0.2-py3-none-any.whl", "ccc"),
+		}, false, ""},
+	}
+	for _, test := range tests {
+		verified, diffs := VerifyAssets(test.assets, localHashes, distDir)
+		if verified != test.verified || strings.Join(diffs, "\n") != test.expected {
+			t.Errorf(`VerifyAssets(%v) = %t, %q, expected %t, %q`,
+				test.assets, verified, diffs, test.verified, test.expected)
+		}
+	}
+}
@@ -4,170 +4,564 @@
 package workflow
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	
 	"github.com/data-engineering-helpers/dppctl/utilities"
 	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/report"
 )
 
-func Check(deplSpec utilities.SpecFile) {
+/**
+ * Check that the resources described by the deployment specification
+ * exist on the cloud services. The checks are independent from each
+ * other: they are run concurrently (at most `parallelism` of them at
+ * the same time), and a failing check does not prevent the others from
+ * being performed. All the failures are recorded in the report, in
+ * the order of the checks whatever the order in which they completed,
+ * and returned together (see errors.Join()).
+ * When a local directory of build artifacts (e.g., `dist/`) is given,
+ * the assets of the packages of the modules are verified against it
+ * (see VerifyAssets()).
+ * The cluster of the compute engine is only looked up when asked for
+ * (`clusterLookup`), as it may be a transient (job) cluster, not running
+ * at the time of the check: a cluster which cannot be found is then
+ * reported as a warning, not as a failure
+ */
+func Check(ctx context.Context, deplSpec utilities.SpecFile,
+	parallelism int, distDir string, clusterLookup bool) (CheckReport, error) {
+	checkReport := CheckReport{}
+
 	// /////////////////////////////////
-	// STS - Caller identity (IAM)
+	// STS - Caller identity (IAM), for every section
 	// /////////////////////////////////
-	stsStruct, err := service.AWSGetCallerIdentity()
+	// Acting with the credentials of another account than the one
+	// of the specification is refused
+	identities, err := Preflight(ctx, deplSpec)
+	checkReport.Identities = identities
 	if err != nil {
-		log.Print(err)
+		log.Println("Preflight failed:", err)
+		checkReport.Failures = append(checkReport.Failures, err.Error())
+		return checkReport, err
 	}
-	log.Println("AWS IAM/caller identity:")
-	log.Println(stsStruct)
 
-	//
-	bucketName := deplSpec.StorageContainer.Name
-	bucketPrefix := deplSpec.StorageContainer.Prefix
-	
 	// /////////////////////////////////
-	// AWS S3
+	// Versions of the modules, resolved from their constraints
 	// /////////////////////////////////
-	object_list, err := service.AWSS3List(bucketName, bucketPrefix)
+	deplSpec, err = ResolveVersions(ctx, deplSpec)
 	if err != nil {
-		log.Print(err)
+		log.Println("Version resolution failed:", err)
+		checkReport.Modules = newModuleReports(deplSpec)
+		checkReport.Failures = append(checkReport.Failures, err.Error())
+		return checkReport, err
 	}
 
-	log.Println("List of objects within the following bucket:", bucketName)
-	for _, object_metadata := range object_list {
-		log.Println(object_metadata)
+	// /////////////////////////////////
+	// Local build artifacts, if any
+	// /////////////////////////////////
+	var dist *localDist
+	if distDir != "" {
+		hashes, err := LocalAssetHashes(distDir)
+		if err != nil {
+			checkReport.Modules = newModuleReports(deplSpec)
+			checkReport.Failures = append(checkReport.Failures, err.Error())
+			return checkReport, err
+		}
+		dist = &localDist{distDir, hashes}
 	}
 
 	// /////////////////////////////////
-	// CodeArtifact
+	// Independent checks
 	// /////////////////////////////////
-	// List of the domains
-	//
+	// Every task fills its own fields of the report. The reports of
+	// the modules are allocated beforehand, each module task filling
+	// its own element
+	checkReport.Modules = newModuleReports(deplSpec)
+	tasks := []task{
+		{"storage_container", func(ctx context.Context) error {
+			return checkStorageContainer(ctx, deplSpec, &checkReport)
+		}},
+	}
+	for idx, module := range deplSpec.Container.Modules {
+		module, moduleReport := module, &checkReport.Modules[idx]
+		tasks = append(tasks, task{"artifact_repo", func(ctx context.Context) error {
+			return checkPackageVersions(ctx, deplSpec, module, moduleReport)
+		}}, task{"artifact_repo", func(ctx context.Context) error {
+			return checkPackageVersion(ctx, deplSpec, module, moduleReport)
+		}}, task{"artifact_repo", func(ctx context.Context) error {
+			return checkPackageAssets(ctx, deplSpec, module, moduleReport, dist)
+		}}, task{"artifact_repo", func(ctx context.Context) error {
+			return checkPackageDependencies(ctx, deplSpec, module, moduleReport)
+		}})
+		if module.Image != "" {
+			tasks = append(tasks, task{"container_repo", func(ctx context.Context) error {
+				return checkModuleImage(ctx, deplSpec, module, moduleReport)
+			}})
+		}
+	}
+	checkReport.Dependencies = make([]DependencyReport,
+		len(deplSpec.Container.Dependencies))
+	for idx, dependency := range deplSpec.Container.Dependencies {
+		dependency, dependencyReport := dependency, &checkReport.Dependencies[idx]
+		tasks = append(tasks, task{"artifact_repo", func(ctx context.Context) error {
+			return checkDependency(ctx, deplSpec, dependency, dependencyReport)
+		}})
+	}
+	tasks = append(tasks, []task{
+		{"container_repo", func(ctx context.Context) error {
+			return checkContainerImages(ctx, deplSpec, &checkReport)
+		}},
+		{"airflow", func(ctx context.Context) error {
+			return checkDagObjects(ctx, deplSpec, &checkReport)
+		}},
+		{"airflow", func(ctx context.Context) error {
+			return checkDagFiles(ctx, deplSpec, &checkReport)
+		}},
+		{"airflow", func(ctx context.Context) error {
+			return checkDags(ctx, deplSpec, &checkReport)
+		}},
+	}...)
+	if clusterLookup {
+		tasks = append(tasks, task{"compute_engine", func(ctx context.Context) error {
+			return checkCluster(ctx, deplSpec, &checkReport)
+		}})
+	}
+
+	// The versions of Spark, of Delta Lake and of the compute engine
+	// have to fit together, as checked by the `validate` command
+	failures := []error{}
+	err = utilities.CheckCompatibility(deplSpec)
+	if err != nil {
+		failure := fmt.Errorf("compatibility: %w", err)
+		log.Println("Check failed:", failure)
+		failures = append(failures, failure)
+		checkReport.Failures = append(checkReport.Failures, failure.Error())
+	}
+
+	for idx, err := range runTasks(ctx, parallelism, tasks) {
+		if err == nil {
+			continue
+		}
+		failure := fmt.Errorf("%s: %w", tasks[idx].Section, err)
+		log.Println("Check failed:", failure)
+		failures = append(failures, failure)
+		checkReport.Failures = append(checkReport.Failures, failure.Error())
+	}
+
+	// The packages none of the assets of which has been built locally
+	// are not verified, which is only a warning
+	for _, moduleReport := range checkReport.Modules {
+		if !moduleReport.AssetsSkipped {
+			continue
+		}
+		warning := fmt.Sprintf("artifact_repo: the assets of the %s package, in version %s, are not verified, none of them being in %s",
+			moduleReport.Module, moduleReport.Version, distDir)
+		log.Println("Warning:", warning)
+		checkReport.Warnings = append(checkReport.Warnings, warning)
+	}
+
+	// /////////////////////////////////
+	// Summary
+	// /////////////////////////////////
+	if len(failures) == 0 {
+		log.Println("All the checks passed")
+		return checkReport, nil
+	}
+
+	log.Printf("%d check(s) failed", len(failures))
+	return checkReport, errors.Join(failures...)
+}
+
+// /////////////////////////////////
+// AWS S3
+// /////////////////////////////////
+func checkStorageContainer(ctx context.Context, deplSpec utilities.SpecFile,
+	checkReport *CheckReport) error {
+	bucketName := deplSpec.StorageContainer.Name
+	bucketPrefix := deplSpec.StorageContainer.Prefix
+	checkReport.Bucket = bucketName
+	checkReport.Prefix = bucketPrefix
+
+	objectStorage, err := service.NewObjectStorage(ctx, storageContainerConfig(deplSpec))
+	if err != nil {
+		return err
+	}
+
+	log.Println("Listing the objects within the following bucket:",
+		bucketName)
+	return objectStorage.List(ctx, bucketName, bucketPrefix,
+		service.ListOptions{}, func(object service.S3Object) error {
+			log.Println(report.FormatS3Object(object))
+			checkReport.Objects = append(checkReport.Objects, object)
+			return nil
+		})
+}
+
+// /////////////////////////////////
+// CodeArtifact - versions of the package of a module
+// /////////////////////////////////
+func checkPackageVersions(ctx context.Context, deplSpec utilities.SpecFile,
+	module utilities.Module, moduleReport *ModuleReport) error {
 	caDomainName := deplSpec.ArtifactRepo.Domain
 	caDomainOwner := deplSpec.ArtifactRepo.AccountId
 	caRepoName := deplSpec.ArtifactRepo.Name
-	caFormatStr := deplSpec.ArtifactRepo.Format
-	packageName := deplSpec.Container.Module.Name
-	packageVersion := deplSpec.Container.Module.Version
-
-	caFormat, err := service.AWSCodeArtifactFormatFromString(caFormatStr)
-    if err != nil {
-		errMsg := fmt.Sprintf("The %s CodeArtifact format is not known",
-		caFormatStr)
-        log.Fatalf(errMsg, err)
-    }
-
-	pkgVersions, err := service.AWSCodeArtifactListPackageVersions(caDomainName,
-		caDomainOwner, caRepoName, caFormat, packageName)
-    if err != nil {
-		errMsg := fmt.Sprintf("No versioned package can be retrieved from CodeArtifact repository for Domain-name=%s Domain-owner=%s Repo-name=%s Format=%s Pkg-name=%s",
-		caDomainName, caDomainOwner, caRepoName, caFormat, packageName)
-        log.Fatalf(errMsg, err)
-    }
-	
-	log.Println("List of versioned packages within the CodeArtifact repository:")
-	for _, pkgVersion := range pkgVersions {
-		log.Println(pkgVersion)
+	caFormat := deplSpec.PackageFormat(module.Format)
+	packageName := module.Name
+
+	artifactRepo, err := service.NewArtifactRepository(ctx, artifactRepoConfig(deplSpec))
+	if err != nil {
+		return err
+	}
+
+	log.Println("Listing the versions of the package within the CodeArtifact repository:",
+		packageName)
+	err = artifactRepo.ListPackageVersions(ctx, caDomainName, caDomainOwner,
+		caRepoName, caFormat, module.Namespace, packageName, service.ListOptions{},
+		func(pkgVersion service.PackageVersion) error {
+			log.Println(report.FormatPackageVersion(pkgVersion))
+			moduleReport.PackageVersions = append(moduleReport.PackageVersions,
+				pkgVersion)
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("no versioned package can be retrieved for Domain-name=%s Domain-owner=%s Repo-name=%s Format=%s Pkg-name=%s: %w",
+			caDomainName, caDomainOwner, caRepoName, caFormat, packageName,
+			err)
+	}
+	return nil
+}
+
+// /////////////////////////////////
+// CodeArtifact - version of the package of a module specified for the deployment
+// /////////////////////////////////
+func checkPackageVersion(ctx context.Context, deplSpec utilities.SpecFile,
+	module utilities.Module, moduleReport *ModuleReport) error {
+	caDomainName := deplSpec.ArtifactRepo.Domain
+	caDomainOwner := deplSpec.ArtifactRepo.AccountId
+	caRepoName := deplSpec.ArtifactRepo.Name
+	caFormat := deplSpec.PackageFormat(module.Format)
+	packageName := module.Name
+	packageVersion := module.Version
+
+	artifactRepo, err := service.NewArtifactRepository(ctx, artifactRepoConfig(deplSpec))
+	if err != nil {
+		return err
 	}
 
-	//
-	pkgDetails, err := service.AWSCodeArtifactDescribePackageVersion(caDomainName,
-		caDomainOwner, caRepoName, caFormat, packageName, packageVersion)
-    if err != nil {
-		errMsg := fmt.Sprintf("No versioned package can be retrieved from CodeArtifact repository for Domain-name=%s Domain-owner=%s Repo-name=%s Format=%s Pkg-name=%s Pkg-version=%s",
+	pkgDetails, err := artifactRepo.DescribePackageVersion(ctx, caDomainName,
+		caDomainOwner, caRepoName, caFormat, module.Namespace, packageName,
+		packageVersion)
+	if err != nil {
+		return fmt.Errorf("the versioned package cannot be retrieved for Domain-name=%s Domain-owner=%s Repo-name=%s Format=%s Pkg-name=%s Pkg-version=%s: %w",
 			caDomainName, caDomainOwner, caRepoName, caFormat,
-			packageName, packageVersion)
-        log.Fatalf(errMsg + ": %v", err)
-    }
-	
-	log.Println("Details for the versioned package within the CodeArtifact repository:", pkgDetails)
+			packageName, packageVersion, err)
+	}
 
-	// /////////////////////////////////
-	// Elastic Container Registry (ECR)
-	// /////////////////////////////////
+	log.Println("Details for the versioned package within the CodeArtifact repository:",
+		report.FormatPackageVersionDetail(pkgDetails))
+	moduleReport.PackageVersion = &pkgDetails
+	return nil
+}
 
-	/*
-	// List of the repositories
-	ecrRepoList, err := service.AWSECRListRepositories()
-    if err != nil {
-		errMsg := fmt.Sprintf("No repository can be retrieved from ECR service")
-        log.Fatalf(errMsg, err)
-    }
-	
-	log.Println("List of repositories within the ECR service:")
-	for _, ecrRepo := range ecrRepoList {
-		log.Println(ecrRepo)
+// /////////////////////////////////
+// CodeArtifact - assets (files) of the version of the package of a module,
+// verified against the local build artifacts, if any
+// /////////////////////////////////
+func checkPackageAssets(ctx context.Context, deplSpec utilities.SpecFile,
+	module utilities.Module, moduleReport *ModuleReport, dist *localDist) error {
+	caRepoName := deplSpec.ArtifactRepo.Name
+	artifactRepo, err := service.NewArtifactRepository(ctx, artifactRepoConfig(deplSpec))
+	if err != nil {
+		return err
+	}
+
+	err = artifactRepo.ListPackageVersionAssets(ctx,
+		deplSpec.ArtifactRepo.Domain, deplSpec.ArtifactRepo.AccountId,
+		caRepoName, deplSpec.PackageFormat(module.Format), module.Namespace,
+		module.Name, module.Version, service.ListOptions{},
+		func(asset service.PackageAsset) error {
+			log.Println("Asset of the versioned package:", asset.Name,
+				service.AssetHashSHA256+"="+asset.Hashes[service.AssetHashSHA256])
+			moduleReport.Assets = append(moduleReport.Assets, asset)
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("the assets of the %s package, in version %s, cannot be retrieved from the %s repository: %w",
+			module.Name, module.Version, caRepoName, err)
+	}
+
+	if dist == nil {
+		return nil
+	}
+	verified, diffs := VerifyAssets(moduleReport.Assets, dist.Hashes, dist.Dir)
+	if !verified {
+		// The warning is reported once all the checks are completed
+		moduleReport.AssetsSkipped = true
+		log.Printf("None of the assets of the %s package, in version %s, is in %s: they are not verified",
+			module.Name, module.Version, dist.Dir)
+		return nil
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("the assets of the %s package, in version %s, are not the ones built into %s:\n  - %s",
+			module.Name, module.Version, dist.Dir, strings.Join(diffs, "\n  - "))
+	}
+	log.Printf("The assets of the %s package, in version %s, are the ones built into %s",
+		module.Name, module.Version, dist.Dir)
+	return nil
+}
+
+// /////////////////////////////////
+// CodeArtifact - dependencies declared by the version of the package
+// of a module, compared with the dependencies of the specification
+// /////////////////////////////////
+func checkPackageDependencies(ctx context.Context,
+	deplSpec utilities.SpecFile, module utilities.Module,
+	moduleReport *ModuleReport) error {
+	caRepoName := deplSpec.ArtifactRepo.Name
+	artifactRepo, err := service.NewArtifactRepository(ctx, artifactRepoConfig(deplSpec))
+	if err != nil {
+		return err
 	}
-	*/
 
-	// List of the image tags
-	//
+	requirements := []service.PackageDependency{}
+	err = artifactRepo.ListPackageVersionDependencies(ctx,
+		deplSpec.ArtifactRepo.Domain, deplSpec.ArtifactRepo.AccountId,
+		caRepoName, deplSpec.PackageFormat(module.Format), module.Namespace,
+		module.Name, module.Version,
+		func(requirement service.PackageDependency) error {
+			log.Println("Dependency of the versioned package:",
+				requirement.Package, requirement.VersionRequirement)
+			requirements = append(requirements, requirement)
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("the dependencies of the %s package, in version %s, cannot be retrieved from the %s repository: %w",
+			module.Name, module.Version, caRepoName, err)
+	}
+
+	requirementReports, conflicts := CheckRequirements(deplSpec, module,
+		requirements)
+	moduleReport.Requirements = requirementReports
+	if len(conflicts) > 0 {
+		return fmt.Errorf("the dependencies of the %s package conflict with the specification:\n  - %s",
+			module.Name, strings.Join(conflicts, "\n  - "))
+	}
+	return nil
+}
+
+// /////////////////////////////////
+// Elastic Container Registry (ECR) - image of a module, tagged with its version
+// /////////////////////////////////
+func checkModuleImage(ctx context.Context, deplSpec utilities.SpecFile,
+	module utilities.Module, moduleReport *ModuleReport) error {
+	containerRegistry, err := service.NewContainerRegistry(ctx, containerRepoConfig(deplSpec))
+	if err != nil {
+		return err
+	}
+
+	imageDetail, err := containerRegistry.DescribeImageTag(ctx, module.Image,
+		module.Version)
+	if err != nil {
+		return fmt.Errorf("the %s image tag of the %s module cannot be found in the %s ECR repository: %w",
+			module.Version, module.Name, module.Image, err)
+	}
+
+	log.Println("Image of the module found within the ECR service:",
+		module.Image+":"+module.Version, report.FormatImageDetail(imageDetail))
+	moduleReport.Image = &imageDetail
+	return nil
+}
+
+// /////////////////////////////////
+// CodeArtifact - version of a dependency
+// /////////////////////////////////
+func checkDependency(ctx context.Context, deplSpec utilities.SpecFile,
+	dependency utilities.Dependency, dependencyReport *DependencyReport) error {
+	caDomainName := deplSpec.ArtifactRepo.Domain
+	caDomainOwner := deplSpec.ArtifactRepo.AccountId
+	caRepoName := deplSpec.ArtifactRepo.Name
+	caFormat := deplSpec.PackageFormat(dependency.Format)
+	*dependencyReport = DependencyReport{Name: dependency.Name,
+		Version: dependency.Version, Format: caFormat}
+
+	artifactRepo, err := service.NewArtifactRepository(ctx, artifactRepoConfig(deplSpec))
+	if err != nil {
+		return err
+	}
+
+	log.Println("Listing the versions of the dependency within the CodeArtifact repository:",
+		dependency.Name)
+	pkgVersions := []service.PackageVersion{}
+	err = artifactRepo.ListPackageVersions(ctx, caDomainName, caDomainOwner,
+		caRepoName, caFormat, dependency.Namespace, dependency.Name,
+		service.ListOptions{},
+		func(pkgVersion service.PackageVersion) error {
+			pkgVersions = append(pkgVersions, pkgVersion)
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("the versions of the %s dependency cannot be retrieved for Domain-name=%s Domain-owner=%s Repo-name=%s Format=%s: %w",
+			dependency.Name, caDomainName, caDomainOwner, caRepoName,
+			caFormat, err)
+	}
+
+	pkgVersion, err := FindDependencyVersion(dependency, pkgVersions)
+	dependencyReport.Status = pkgVersion.Status
+	if err != nil {
+		return err
+	}
+	log.Println("Dependency found within the CodeArtifact repository:",
+		report.FormatPackageVersion(pkgVersion))
+	return nil
+}
+
+/**
+ * Find the version of a dependency among the versions of the package
+ * listed on the artifact repository. A missing version is an error,
+ * as well as a version which is not published (e.g., unlisted or
+ * archived), as the package managers would not install it
+ */
+func FindDependencyVersion(dependency utilities.Dependency,
+	pkgVersions []service.PackageVersion) (service.PackageVersion, error) {
+	for _, pkgVersion := range pkgVersions {
+		if pkgVersion.Version != dependency.Version {
+			continue
+		}
+		if pkgVersion.Status != service.PackageVersionStatusPublished {
+			return pkgVersion, fmt.Errorf("the %s dependency, in version %s, is %s (instead of %s) in the artifact repository",
+				dependency.Name, dependency.Version,
+				strings.ToLower(pkgVersion.Status),
+				strings.ToLower(service.PackageVersionStatusPublished))
+		}
+		return pkgVersion, nil
+	}
+
+	return service.PackageVersion{}, fmt.Errorf("the %s dependency, in version %s, cannot be found in the artifact repository (%d version(s) found)",
+		dependency.Name, dependency.Version, len(pkgVersions))
+}
+
+// /////////////////////////////////
+// Elastic Container Registry (ECR)
+// /////////////////////////////////
+func checkContainerImages(ctx context.Context, deplSpec utilities.SpecFile,
+	checkReport *CheckReport) error {
 	ecrRepoName := deplSpec.ContainerRepo.Name
-	ecrImgList, err := service.AWSECRListImages(ecrRepoName)
-    if err != nil {
-		errMsg := fmt.Sprintf("No image can be retrieved from ECR service for the %s repository",
-			ecrRepoName)
-        log.Fatalf(errMsg, err)
-    }
-	
-	log.Println("List of repositories within the ECR service for", ecrRepoName)
-	for _, ecrImg := range ecrImgList {
-		log.Println(ecrImg)
+	containerRegistry, err := service.NewContainerRegistry(ctx, containerRepoConfig(deplSpec))
+	if err != nil {
+		return err
 	}
-	
+
 	// Description of the images
-	//
-	ecrImgDetailList, err := service.AWSECRDescribeImages(ecrRepoName)
-    if err != nil {
-		errMsg := fmt.Sprintf("No image can be retrieved from ECR service for the %s repository",
-			ecrRepoName)
-        log.Fatalf(errMsg, err)
-    }
-	
-	log.Println("List of image details within the ECR service for", ecrRepoName)
-	for _, ecrImg := range ecrImgDetailList {
-		log.Println(ecrImg)
-	}
-	
-	// /////////////////////////////////
-	// MWAA/Airflow
-	// /////////////////////////////////
-	// Create a one-time MWAA CLI token
-	mwaaEnv := deplSpec.Airflow.Domain
-	webServerHostname, cliToken, _,
-		err := service.AWSAirflowCreateLoginToken(mwaaEnv)
+	log.Println("Listing the images within the ECR service for",
+		ecrRepoName)
+	err = containerRegistry.DescribeImages(ctx, ecrRepoName,
+		service.ListOptions{}, func(image service.ImageDetail) error {
+			log.Println(report.FormatImageDetail(image))
+			checkReport.Images = append(checkReport.Images, image)
+			return nil
+		})
 	if err != nil {
-		log.Print(err)
+		return fmt.Errorf("no image detail can be retrieved for the %s repository: %w",
+			ecrRepoName, err)
 	}
-	mwaaStr := fmt.Sprintf("hostname=%s token=%s", webServerHostname, cliToken)
-	log.Println("MWAA/Airflow CLI token created: ", mwaaStr)
+	return nil
+}
 
-	// Invoke the MWAA CLI API for the specific command (here, the list of DAGs)
-	//command := "version"
-	command := "dags list -o json"
-	stdoutStr, err := service.AWSAirflowCLI(webServerHostname, cliToken,
-		command)
+// /////////////////////////////////
+// AWS S3 - DAG folder of Airflow
+// /////////////////////////////////
+func checkDagObjects(ctx context.Context, deplSpec utilities.SpecFile,
+	checkReport *CheckReport) error {
+	bucketName := deplSpec.Airflow.StorageContainer.Name
+	bucketPrefix := deplSpec.Airflow.StorageContainer.Prefix
+	checkReport.DagBucket = bucketName
+	checkReport.DagPrefix = bucketPrefix
+
+	objectStorage, err := service.NewObjectStorage(ctx, airflowConfig(deplSpec))
 	if err != nil {
-		log.Print(err)
+		return err
 	}
-	//log.Println("MWAA/Airflow CLI response: ", stdoutStr)
 
-	// Parse the output when the command is "dags list -o json"
-	mwaaDagMetadataList, err := utilities.ParseAWSMWAADagListOutput(stdoutStr)
+	log.Println("Listing the DAG files within the following bucket:",
+		bucketName)
+	dagPrefix := dagObjectPrefix(bucketPrefix)
+	return objectStorage.List(ctx, bucketName, dagPrefix,
+		service.ListOptions{}, func(object service.S3Object) error {
+			if strings.HasSuffix(object.Key, "/") ||
+				!strings.HasPrefix(object.Key, dagPrefix) {
+				return nil
+			}
+			checkReport.DagObjects = append(checkReport.DagObjects, object)
+			return nil
+		})
+}
+
+// /////////////////////////////////
+// Local DAG files, to be uploaded onto the DAG folder of Airflow
+// /////////////////////////////////
+func checkDagFiles(ctx context.Context, deplSpec utilities.SpecFile,
+	checkReport *CheckReport) error {
+	sourceDir := deplSpec.Airflow.Dag.SourceDir
+	checkReport.DagSourceDir = sourceDir
+
+	// Without any local DAG directory, there is nothing to be uploaded
+	if sourceDir == "" {
+		return nil
+	}
+	dagFiles, err := localDagFiles(sourceDir)
 	if err != nil {
-		log.Print(err)
+		return err
 	}
-	//log.Println("MWAA/Airflow DAGs: ", mwaaDagMetadataList)
+	checkReport.DagFiles = dagFiles
+	return nil
+}
 
-	// Retrieve the DAGs, for which the name is matching the given pattern
+// /////////////////////////////////
+// MWAA/Airflow
+// /////////////////////////////////
+// Retrieve the DAGs, for which the name is matching the given pattern
+func checkDags(ctx context.Context, deplSpec utilities.SpecFile,
+	checkReport *CheckReport) error {
+	mwaaEnv := deplSpec.Airflow.Domain
 	namePattern := deplSpec.Airflow.Dag.NamePattern
+	orchestrator, err := service.NewOrchestrator(ctx, airflowConfig(deplSpec))
+	if err != nil {
+		return err
+	}
+
 	log.Println("Retrieving all the Airflow DAG matching the following name pattern:",
 		namePattern)
-
-	dagList, err := utilities.ExtractMatchingAWSMWAADagList(mwaaDagMetadataList,
+	checkReport.Dags, err = listMatchingDags(ctx, orchestrator, mwaaEnv,
 		namePattern)
-	
-	log.Println("List of MWAA/Airflow DAGs mathcing the name pattern:", dagList)
+	return err
 }
 
+// /////////////////////////////////
+// Compute engine (e.g., AWS EMR) - the cluster may not be running
+// (e.g., a transient job cluster), which is only a warning
+// /////////////////////////////////
+func checkCluster(ctx context.Context, deplSpec utilities.SpecFile,
+	checkReport *CheckReport) error {
+	clusterName := deplSpec.ComputeEngine.Cluster.Name
+	computeEngine, err := service.NewComputeEngine(ctx, computeEngineConfig(deplSpec))
+	if err != nil {
+		return err
+	}
+
+	log.Println("Retrieving the details of the compute engine cluster:",
+		clusterName)
+	clusterDetails, err := computeEngine.DescribeCluster(ctx, clusterName)
+	if err != nil {
+		warning := fmt.Sprintf("compute_engine: the %s cluster cannot be looked up: %v",
+			clusterName, err)
+		log.Println("Warning:", warning)
+		checkReport.Warnings = append(checkReport.Warnings, warning)
+		return nil
+	}
+	log.Println(report.FormatClusterDetail(clusterDetails))
+	checkReport.Cluster = &clusterDetails
+	return nil
+}
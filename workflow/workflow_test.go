@@ -0,0 +1,109 @@
+//
+// File: https://github.com/data-engineering-helpers/dppctl/blob/main/workflow/workflow_test.go
+//
+package workflow
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/data-engineering-helpers/dppctl/service"
+	"github.com/data-engineering-helpers/dppctl/utilities"
+)
+
+/**
+ * Check that the versions of Spark, of Delta Lake and of the compute
+ * engine which do not fit together fail the check, and are refused
+ * by the deployment before any call to the services
+ */
+func TestIncompatibleVersions(t *testing.T) {
+	// Delta Lake 2.4.0 fits Spark 3.4, which EMR 6.9.0 does not ship
+	deplSpec := fakeProviderSpec()
+	deplSpec.ComputeEngine.Engine = "emr"
+	deplSpec.ComputeEngine.Cluster.Version = "emr-6.9.0"
+	deplSpec.Container.Dependencies = []utilities.Dependency{
+		{Name: "pyspark", Version: "3.4.0"},
+		{Name: "delta-spark", Version: "2.4.0"},
+	}
+	expected := "container.dependencies[0].version: pyspark 3.4.0 does not match Spark 3.3.0 of the AWS EMR emr-6.9.0 release"
+
+	checkReport, err := Check(context.Background(), deplSpec, 1, "",
+		false)
+	if err == nil || len(checkReport.Failures) == 0 ||
+		checkReport.Failures[0] != "compatibility: "+expected {
+		t.Errorf(`Check() = %q, expected the %q failure first`,
+			checkReport.Failures, "compatibility: "+expected)
+	}
+
+	testProvider.identityCalls = 0
+	_, err = Deploy(context.Background(), deplSpec, nil)
+	if err == nil || !strings.Contains(err.Error(), expected) ||
+		testProvider.identityCalls != 0 {
+		t.Errorf(`Deploy() = %v, with %d identity call(s), expected %q before any call`,
+			err, testProvider.identityCalls, expected)
+	}
+}
+
+/**
+ * Check that a dependency is found only when its version is published
+ * on the artifact repository
+ */
+func TestFindDependencyVersion(t *testing.T) {
+	pkgVersions := []service.PackageVersion{
+		{Package: "pyspark", Version: "3.3.0", Status: "Published"},
+		{Package: "pyspark", Version: "3.3.1", Status: "Unlisted"},
+		{Package: "pyspark", Version: "3.2.0", Status: "Archived"},
+	}
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{"3.3.0", ""},
+		{"3.3.1", "is unlisted"},
+		{"3.2.0", "is archived"},
+		{"3.4.0", "cannot be found"},
+	}
+	for _, test := range tests {
+		dependency := utilities.Dependency{Name: "pyspark",
+			Version: test.version}
+		_, err := FindDependencyVersion(dependency, pkgVersions)
+		switch {
+		case test.expected == "" && err != nil:
+			t.Errorf(`FindDependencyVersion(%s) = %v, expected no error`,
+				test.version, err)
+		case test.expected != "" && (err == nil ||
+			!strings.Contains(err.Error(), test.expected)):
+			t.Errorf(`FindDependencyVersion(%s) = %v, expected %q`,
+				test.version, err, test.expected)
+		}
+	}
+}
+
+/**
+ * Check that only the objects under the DAG prefix are listed, and not
+ * those of the sibling prefixes (e.g., dags_old/ for dags)
+ */
+func TestCheckDagObjects(t *testing.T) {
+	deplSpec := fakeProviderSpec()
+	deplSpec.Airflow.StorageContainer.Name = "example-bucket"
+	deplSpec.Airflow.StorageContainer.Prefix = "dags"
+	testProvider.objectStorage = &fakeObjectStorage{objects: []service.S3Object{
+		{Key: "dags/"},
+		{Key: "dags/example.py"},
+		{Key: "dags/sub/other.py"},
+		{Key: "dags_old/example.py"},
+	}}
+	defer func() { testProvider.objectStorage = nil }()
+
+	checkReport := CheckReport{}
+	err := checkDagObjects(context.Background(), deplSpec, &checkReport)
+	keys := []string{}
+	for _, object := range checkReport.DagObjects {
+		keys = append(keys, object.Key)
+	}
+	expected := "dags/example.py,dags/sub/other.py"
+	if err != nil || strings.Join(keys, ",") != expected {
+		t.Errorf(`checkDagObjects() = %v, %v, expected %s`, keys, err, expected)
+	}
+}